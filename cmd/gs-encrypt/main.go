@@ -0,0 +1,80 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command gs-encrypt encrypts property values offline, the encrypted values are decrypted
+// by the applications with the same key.
+//
+//	gs-encrypt -genkey > encrypt.key
+//	SPRING_ENCRYPT_KEY_FILE=encrypt.key gs-encrypt 'p@ssword'
+//	{cipher}3q2+7w...
+//
+// The values are read from the standard input when there is no argument, one per line.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-spring.dev/spring/conf/crypt"
+)
+
+func main() {
+	genKey := flag.Bool("genkey", false, "print a new random key")
+	marker := flag.String("marker", "{cipher}", "the prefix of the encrypted values")
+	flag.Parse()
+
+	if err := run(*genKey, *marker, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "gs-encrypt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(genKey bool, marker string, args []string) error {
+	if genKey {
+		key, err := crypt.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+	}
+
+	c, err := crypt.FromEnv()
+	if err != nil {
+		return fmt.Errorf("%w, set %s or %s", err, crypt.KeyEnv, crypt.KeyFileEnv)
+	}
+
+	if len(args) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			args = append(args, scanner.Text())
+		}
+		if err = scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range args {
+		ciphertext, err := c.Encrypt(s)
+		if err != nil {
+			return err
+		}
+		fmt.Println(marker + ciphertext)
+	}
+	return nil
+}
@@ -117,6 +117,11 @@ func BindValue(p *Properties, v reflect.Value, t reflect.Type, param BindParam,
 		return fmt.Errorf("bind %s error: %w", param.Path, err)
 	}
 
+	// the keys are matched in the relaxed form.
+	if key, ok := p.Lookup(param.Key); ok {
+		param.Key = key
+	}
+
 	switch v.Kind() {
 	case reflect.Map:
 		return bindMap(p, v, t, param, filter)
@@ -146,11 +151,11 @@ func BindValue(p *Properties, v reflect.Value, t reflect.Type, param BindParam,
 		out := fnValue.Call([]reflect.Value{reflect.ValueOf(val)})
 		if !out[1].IsNil() {
 			err = out[1].Interface().(error)
-			return fmt.Errorf("bind %s error: %w", param.Path, err)
+			return fmt.Errorf("bind %s error: %w", param.Path, withOrigin(p, param, err))
 		}
 
 		if err = Validate(param.Validate, out[0].Interface()); nil != err {
-			return fmt.Errorf("validate %s error: %w", param.Path, err)
+			return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 		}
 
 		v.Set(out[0])
@@ -162,45 +167,45 @@ func BindValue(p *Properties, v reflect.Value, t reflect.Type, param BindParam,
 		var u uint64
 		if u, err = strconv.ParseUint(val, 0, 0); err == nil {
 			if err = Validate(param.Validate, u); err != nil {
-				return fmt.Errorf("validate %s error: %w", param.Path, err)
+				return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 			}
 			v.SetUint(u)
 			return nil
 		}
-		return fmt.Errorf("bind %s error: %w", param.Path, err)
+		return fmt.Errorf("bind %s error: %w", param.Path, withOrigin(p, param, err))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		var i int64
 		if i, err = strconv.ParseInt(val, 0, 0); err == nil {
 			if err = Validate(param.Validate, i); err != nil {
-				return fmt.Errorf("validate %s error: %w", param.Path, err)
+				return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 			}
 			v.SetInt(i)
 			return nil
 		}
-		return fmt.Errorf("bind %s error: %w", param.Path, err)
+		return fmt.Errorf("bind %s error: %w", param.Path, withOrigin(p, param, err))
 	case reflect.Float32, reflect.Float64:
 		var f float64
 		if f, err = strconv.ParseFloat(val, 64); err == nil {
 			if err = Validate(param.Validate, f); err != nil {
-				return fmt.Errorf("validate %s error: %w", param.Path, err)
+				return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 			}
 			v.SetFloat(f)
 			return nil
 		}
-		return fmt.Errorf("bind %s error: %w", param.Path, err)
+		return fmt.Errorf("bind %s error: %w", param.Path, withOrigin(p, param, err))
 	case reflect.Bool:
 		var b bool
 		if b, err = strconv.ParseBool(val); err == nil {
 			if err = Validate(param.Validate, b); err != nil {
-				return fmt.Errorf("validate %s error: %w", param.Path, err)
+				return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 			}
 			v.SetBool(b)
 			return nil
 		}
-		return fmt.Errorf("bind %s error: %w", param.Path, err)
+		return fmt.Errorf("bind %s error: %w", param.Path, withOrigin(p, param, err))
 	case reflect.String:
 		if err = Validate(param.Validate, val); err != nil {
-			return fmt.Errorf("validate %s error: %w", param.Path, err)
+			return fmt.Errorf("validate %s error: %w", param.Path, withOrigin(p, param, err))
 		}
 		v.SetString(val)
 		return nil
@@ -261,10 +266,11 @@ func getSlice(p *Properties, et reflect.Type, param BindParam) (*Properties, err
 	}
 
 	// properties defined as string and needs to split into []string.
-	var strVal string
+	var strVal, origin string
 	{
 		if p.Has(param.Key) {
 			strVal = p.Get(param.Key)
+			origin = p.Origin(param.Key)
 		} else {
 			if !param.Tag.HasDef {
 				return nil, fmt.Errorf("property %q: %w", param.Key, errNotExist)
@@ -303,7 +309,7 @@ func getSlice(p *Properties, et reflect.Type, param BindParam) (*Properties, err
 	p = New()
 	for i, s := range arrVal {
 		k := fmt.Sprintf("%s[%d]", param.Key, i)
-		_ = p.store(k, s)
+		_ = p.store(k, s, origin)
 	}
 	return p, nil
 }
@@ -422,9 +428,33 @@ func bindStruct(p *Properties, v reflect.Value, t reflect.Type, param BindParam,
 	return nil
 }
 
+// withOrigin adds the origin of the property to the error when it's known.
+func withOrigin(p *Properties, param BindParam, err error) error {
+	if origin := p.Origin(param.Key); origin != "" {
+		return fmt.Errorf("property %q from %s: %w", param.Key, origin, err)
+	}
+	return err
+}
+
 // resolve returns property references processed property value.
 func resolve(p *Properties, param BindParam) (string, error) {
+	key, found := p.Lookup(param.Key)
+	if fn, arg, bare, ok := lookupResolver(param.Tag.Key); ok && !(bare && found) {
+		val, err := resolveBy(p, param, fn, arg)
+		if err == nil || !bare {
+			return val, err
+		}
+		// `${prefix}` with a failed Resolver is a missing property.
+		return "", fmt.Errorf("property %q: %w", param.Key, errNotExist)
+	}
+	if found {
+		param.Key = key
+	}
 	if val := p.storage.Get(param.Key); val != "" {
+		// the decrypted values aren't resolved, they may contain `${` literally.
+		if isEncrypted(val) {
+			return decrypt(param.Key, val)
+		}
 		return resolveString(p, val)
 	}
 	if param.Tag.HasDef {
@@ -448,6 +478,14 @@ func resolveString(p *Properties, s string) (string, error) {
 
 	for i := 0; i < length; i++ {
 		if s[i] == '$' {
+			// `$${` is a literal `${`.
+			if count == 0 && i < length-2 && s[i+1] == '$' && s[i+2] == '{' {
+				s1, err := resolveString(p, s[i+3:])
+				if err != nil {
+					return "", err
+				}
+				return s[:i] + "${" + s1, nil
+			}
 			if i < length-1 && s[i+1] == '{' {
 				if count == 0 {
 					start = i
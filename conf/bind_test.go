@@ -18,6 +18,8 @@ package conf
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -1346,6 +1348,145 @@ func TestBind_Splitter(t *testing.T) {
 	})
 }
 
+func TestBind_Relaxed(t *testing.T) {
+	p := assert.Must(Map(map[string]interface{}{
+		"server": map[string]interface{}{
+			"max-conns":    100,
+			"read_timeout": "3s",
+			"Hosts":        []string{"a.com", "b.com"},
+			"labels": map[string]interface{}{
+				"zone": "a",
+			},
+		},
+	}))
+	var s struct {
+		MaxConns    int               `value:"${maxConns}"`
+		ReadTimeout time.Duration     `value:"${READ-TIMEOUT}"`
+		Hosts       []string          `value:"${hosts}"`
+		Labels      map[string]string `value:"${Labels}"`
+	}
+	err := p.Bind(&s, Key("SERVER"))
+	assert.Nil(t, err)
+	assert.Equal(t, s.MaxConns, 100)
+	assert.Equal(t, s.ReadTimeout, 3*time.Second)
+	assert.Equal(t, s.Hosts, []string{"a.com", "b.com"})
+	assert.Equal(t, s.Labels, map[string]string{"zone": "a"})
+
+	str, err := p.Resolve("${server.max_conns}")
+	assert.Nil(t, err)
+	assert.Equal(t, str, "100")
+
+	key, ok := p.Lookup("server.hosts[1]")
+	assert.True(t, ok)
+	assert.Equal(t, key, "server.Hosts[1]")
+	_, ok = p.Lookup("server.hosts[2]")
+	assert.False(t, ok)
+
+	// the keys are relaxed within a path element only.
+	p = assert.Must(Map(map[string]interface{}{
+		"dbuser": "alice",
+	}))
+	var user string
+	err = p.Bind(&user, Key("db.user:=root"))
+	assert.Nil(t, err)
+	assert.Equal(t, user, "root")
+	_, ok = p.Lookup("db.user")
+	assert.False(t, ok)
+}
+
+func TestBind_Resolver(t *testing.T) {
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("CONF_RESOLVER_HOME", "/home/go")
+		p := assert.Must(Map(map[string]interface{}{
+			"name": "HOME",
+		}))
+		var s string
+		err := p.Bind(&s, Tag("${env:CONF_RESOLVER_${name}}"))
+		assert.Nil(t, err)
+		assert.Equal(t, s, "/home/go")
+		err = p.Bind(&s, Tag("${env:CONF_RESOLVER_NONE:=none}"))
+		assert.Nil(t, err)
+		assert.Equal(t, s, "none")
+		err = p.Bind(&s, Tag("${env:CONF_RESOLVER_NONE}"))
+		assert.Error(t, err, `resolve "env:CONF_RESOLVER_NONE" error: env "CONF_RESOLVER_NONE": not exist`)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "secret")
+		assert.Nil(t, os.WriteFile(file, []byte("p@ssword\n"), 0600))
+		var s struct {
+			Password string `value:"${file:${db.path}}"`
+		}
+		err := assert.Must(Map(map[string]interface{}{
+			"db.path": file,
+		})).Bind(&s, Key("db"))
+		assert.Nil(t, err)
+		assert.Equal(t, s.Password, "p@ssword")
+	})
+
+	t.Run("base64", func(t *testing.T) {
+		var s string
+		err := New().Bind(&s, Tag("${base64:aGVsbG8=}"))
+		assert.Nil(t, err)
+		assert.Equal(t, s, "hello")
+	})
+
+	t.Run("random", func(t *testing.T) {
+		var s string
+		err := New().Bind(&s, Tag("${random.uuid}"))
+		assert.Nil(t, err)
+		assert.Matches(t, s, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+		for i := 0; i < 20; i++ {
+			var n int
+			err = New().Bind(&n, Tag("${random.int(1,3)}"))
+			assert.Nil(t, err)
+			assert.True(t, n == 1 || n == 2)
+		}
+		var n int
+		err = New().Bind(&n, Tag("${random.int(3,1)}"))
+		assert.Error(t, err, "random.int max should be greater than min")
+	})
+
+	t.Run("property", func(t *testing.T) {
+		p := assert.Must(Map(map[string]interface{}{
+			"env":    "prod",
+			"random": map[string]interface{}{"int": 7},
+		}))
+		var s string
+		err := p.Bind(&s, Tag("${env:=dev}"))
+		assert.Nil(t, err)
+		assert.Equal(t, s, "prod")
+		s, err = p.Resolve("${env}-${random.int}")
+		assert.Nil(t, err)
+		assert.Equal(t, s, "prod-7")
+		err = New().Bind(&s, Tag("${env:=dev}"))
+		assert.Nil(t, err)
+		assert.Equal(t, s, "dev")
+		err = New().Bind(&s, Tag("${env}"))
+		assert.Error(t, err, `bind string error: property "env": not exist`)
+	})
+
+	t.Run("register", func(t *testing.T) {
+		RegisterResolver("upper", func(arg string) (string, error) {
+			return strings.ToUpper(arg), nil
+		})
+		defer RemoveResolver("upper")
+		s, err := New().Resolve("${upper:go}-${upper(spring)}")
+		assert.Nil(t, err)
+		assert.Equal(t, s, "GO-SPRING")
+	})
+
+	t.Run("escape", func(t *testing.T) {
+		p := assert.Must(Map(map[string]interface{}{
+			"a": "A",
+		}))
+		s, err := p.Resolve("${a}:$${a}:${a}")
+		assert.Nil(t, err)
+		assert.Equal(t, s, "A:${a}:A")
+	})
+}
+
 func TestBind_Converter(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
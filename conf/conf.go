@@ -42,8 +42,12 @@ type Splitter func(string) ([]string, error)
 // Reader parses []byte into nested map[string]interface{}.
 type Reader func(b []byte) (map[string]interface{}, error)
 
+// lineReader returns the line numbers of the flattened keys.
+type lineReader func(b []byte) map[string]int
+
 var (
 	readers    = map[string]Reader{}
+	lines      = map[string]lineReader{}
 	splitters  = map[string]Splitter{}
 	converters = map[reflect.Type]utils.Converter{}
 )
@@ -54,6 +58,11 @@ func init() {
 	RegisterReader(yaml.Read, ".yaml", ".yml")
 	RegisterReader(toml.Read, ".toml", ".tml")
 
+	// the origins of the properties read from files have line numbers.
+	registerLineReader(prop.Lines, ".properties")
+	registerLineReader(yaml.Lines, ".yaml", ".yml")
+	registerLineReader(toml.Lines, ".toml", ".tml")
+
 	// converts string into time.Time. The string value may have its own
 	// time format defined after >> splitter, otherwise it uses a default
 	// time format `2006-01-02 15:04:05 -0700`.
@@ -81,6 +90,12 @@ func RegisterReader(r Reader, ext ...string) {
 	}
 }
 
+func registerLineReader(r lineReader, ext ...string) {
+	for _, s := range ext {
+		lines[s] = r
+	}
+}
+
 // RegisterSplitter registers a Splitter and named it.
 func RegisterSplitter(name string, fn Splitter) {
 	splitters[name] = fn
@@ -118,6 +133,7 @@ type Value interface {
 // Java properties isn't strictly verified. Although configuration can store as a tree,
 // but it costs more CPU time when getting properties because it reads property node
 // by node. So `conf` uses a tree to strictly verify and a flat map to store.
+// The origin of each key is recorded when it's given, see From.
 type Properties struct {
 	storage *internal.Storage
 }
@@ -144,13 +160,13 @@ func Load(file string) (*Properties, error) {
 	return p, nil
 }
 
-// Load loads properties from file.
+// Load loads properties from file, the origins of them are the file and the lines.
 func (p *Properties) Load(file string) error {
 	b, err := ioutil.ReadFile(file)
 	if err != nil {
 		return err
 	}
-	return p.Bytes(b, filepath.Ext(file))
+	return p.Bytes(b, filepath.Ext(file), From(file))
 }
 
 // Read creates *Properties from io.Reader, ext is the file name extension.
@@ -163,12 +179,12 @@ func Read(r io.Reader, ext string) (*Properties, error) {
 }
 
 // Read creates *Properties from io.Reader, ext is the file name extension.
-func (p *Properties) Read(r io.Reader, ext string) error {
+func (p *Properties) Read(r io.Reader, ext string, opts ...SetOption) error {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
-	return p.Bytes(b, ext)
+	return p.Bytes(b, ext, opts...)
 }
 
 // Bytes creates *Properties from []byte, ext is the file name extension.
@@ -180,8 +196,9 @@ func Bytes(b []byte, ext string) (*Properties, error) {
 	return p, nil
 }
 
-// Bytes loads properties from []byte, ext is the file name extension.
-func (p *Properties) Bytes(b []byte, ext string) error {
+// Bytes loads properties from []byte, ext is the file name extension. The origin
+// given by From is the file name, and the line numbers are appended to it.
+func (p *Properties) Bytes(b []byte, ext string, opts ...SetOption) error {
 	r, ok := readers[ext]
 	if !ok {
 		return fmt.Errorf("unsupported file type %s", ext)
@@ -190,26 +207,65 @@ func (p *Properties) Bytes(b []byte, ext string) error {
 	if err != nil {
 		return err
 	}
-	return p.Merge(m)
+	arg := newSetArg(opts)
+	if arg.origin == "" {
+		return p.Merge(m)
+	}
+	var keyLines map[string]int
+	if fn := lines[ext]; fn != nil {
+		keyLines = fn(b)
+	}
+	for key, val := range Flatten(m) {
+		origin := arg.origin
+		if line := lineOf(keyLines, key); line > 0 {
+			origin = fmt.Sprintf("%s:%d", origin, line)
+		}
+		if err = p.store(key, val, origin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineOf returns the line of the key, or of its nearest parent.
+func lineOf(keyLines map[string]int, key string) int {
+	for {
+		if line, ok := keyLines[key]; ok {
+			return line
+		}
+		var ok bool
+		if key, ok = parentKey(key); !ok {
+			return 0
+		}
+	}
+}
+
+// parentKey returns the key without its last path element.
+func parentKey(key string) (string, bool) {
+	i := strings.LastIndexAny(key, ".[")
+	if i <= 0 {
+		return "", false
+	}
+	return key[:i], true
 }
 
 // Merge flattens the map and sets all keys and values.
-func (p *Properties) Merge(m map[string]interface{}) error {
+func (p *Properties) Merge(m map[string]interface{}, opts ...SetOption) error {
 	s := Flatten(m)
-	return p.merge(s)
+	return p.merge(s, newSetArg(opts).origin)
 }
 
-func (p *Properties) merge(m map[string]string) error {
+func (p *Properties) merge(m map[string]string, origin string) error {
 	for key, val := range m {
-		if err := p.store(key, val); err != nil {
+		if err := p.store(key, val, origin); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (p *Properties) store(key, val string) error {
-	return p.storage.Set(key, val)
+func (p *Properties) store(key, val, origin string) error {
+	return p.storage.Set(key, val, origin)
 }
 
 func (p *Properties) Copy() *Properties {
@@ -228,6 +284,23 @@ func (p *Properties) Has(key string) bool {
 	return p.storage.Has(key)
 }
 
+// Lookup returns the key stored in a relaxed form of the key, the case, `-` and `_` of
+// the map keys are ignored, so `server.max-conns` is found by `server.maxConns`.
+func (p *Properties) Lookup(key string) (string, bool) {
+	return p.storage.Lookup(key)
+}
+
+// Origin returns where the property comes from, such as `config/app.yaml:3`, `env GS_A`,
+// `arg --a`, or empty when it's unknown. The origin of a list or a map is that of its
+// first element, and an element without origin has the origin of its nearest parent.
+func (p *Properties) Origin(key string) string {
+	key, ok := p.Lookup(key)
+	if !ok {
+		return ""
+	}
+	return p.storage.Origin(key)
+}
+
 type getArg struct {
 	def string
 }
@@ -260,13 +333,35 @@ func (p *Properties) Get(key string, opts ...GetOption) string {
 // means when you set a slice or a map, an existing path will remain
 // when it doesn't exist in the slice or map even they share a same
 // prefix path.
-func (p *Properties) Set(key string, val interface{}) error {
+func (p *Properties) Set(key string, val interface{}, opts ...SetOption) error {
 	if key == "" {
 		return nil
 	}
 	m := make(map[string]string)
 	flatten(key, val, m)
-	return p.merge(m)
+	return p.merge(m, newSetArg(opts).origin)
+}
+
+type setArg struct {
+	origin string
+}
+
+// SetOption is an option of Set, Merge, Bytes and Read, see From.
+type SetOption func(arg *setArg)
+
+// From records the origin of the properties set, such as `env GS_A`.
+func From(origin string) SetOption {
+	return func(arg *setArg) {
+		arg.origin = origin
+	}
+}
+
+func newSetArg(opts []SetOption) setArg {
+	arg := setArg{}
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	return arg
 }
 
 // Resolve resolves string value that contains references to other
@@ -55,6 +55,52 @@ func TestBytes(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestProperties_Origin(t *testing.T) {
+
+	p, err := Load("testdata/application.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("point.list[1]"), "testdata/application.yaml:4")
+	assert.Equal(t, p.Origin("point.list"), "testdata/application.yaml:3")
+	assert.Equal(t, p.Origin("point"), "testdata/application.yaml:3")
+
+	err = p.Bytes([]byte("server:\n  max-conns: abc\n"), ".yaml", From("app.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("server.maxConns"), "app.yaml:2")
+
+	err = p.Set("a", []string{"1", "2"}, From("env GS_A"))
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("a[1]"), "env GS_A")
+	assert.Equal(t, p.Origin("b"), "")
+
+	err = p.Set("a[1]", "3")
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("a[1]"), "")
+	assert.Equal(t, p.Copy().Origin("a[0]"), "env GS_A")
+
+	// the origin of a key replaced by a structure is dropped with the key.
+	err = p.Set("c", "", From("env GS_C"))
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("c"), "env GS_C")
+	err = p.Set("c.d", "1", From("arg -D c.d"))
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("c"), "arg -D c.d")
+	assert.Equal(t, p.Origin("c.e"), "")
+
+	var s struct {
+		MaxConns int `value:"${max-conns}"`
+	}
+	err = p.Bind(&s, Key("server"))
+	assert.Error(t, err, `bind .* error: property "server.max-conns" from app.yaml:2: strconv.ParseInt: parsing "abc": invalid syntax`)
+
+	var v struct {
+		Port int `value:"${port}" expr:"$>0"`
+	}
+	err = p.Set("port", -1, From("arg --port"))
+	assert.Nil(t, err)
+	err = p.Bind(&v)
+	assert.Error(t, err, `validate .* error: property "port" from arg --port: validate failed on "\$>0" for value -1`)
+}
+
 func TestProperties(t *testing.T) {
 	p := assert.Must(Map(map[string]interface{}{
 		"int":   1,
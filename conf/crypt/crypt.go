@@ -0,0 +1,126 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package crypt encrypts and decrypts property values by AES-GCM, the key is read from
+// the environment variable SPRING_ENCRYPT_KEY or the file of SPRING_ENCRYPT_KEY_FILE.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// KeyEnv is the environment variable of the base64 encoded key.
+	KeyEnv = "SPRING_ENCRYPT_KEY"
+
+	// KeyFileEnv is the environment variable of the file containing the base64 encoded key.
+	KeyFileEnv = "SPRING_ENCRYPT_KEY_FILE"
+)
+
+// ErrNoKey is returned when neither KeyEnv nor KeyFileEnv is set.
+var ErrNoKey = errors.New("no encrypt key")
+
+// AESGCM encrypts and decrypts values by AES-GCM, the ciphertext is the base64 encoded
+// nonce followed by the sealed value.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an AESGCM of the key, which is 16, 24 or 32 bytes.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// FromEnv returns an AESGCM of the key read from KeyEnv or KeyFileEnv.
+func FromEnv() (*AESGCM, error) {
+	key, err := LoadKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewAESGCM(key)
+}
+
+// LoadKey reads the base64 encoded key from KeyEnv, or the file of KeyFileEnv.
+func LoadKey() ([]byte, error) {
+	s := os.Getenv(KeyEnv)
+	if s == "" {
+		file := os.Getenv(KeyFileEnv)
+		if file == "" {
+			return nil, ErrNoKey
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		s = string(b)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypt key: %w", err)
+	}
+	return key, nil
+}
+
+// GenerateKey returns a random base64 encoded key of 32 bytes.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt encrypts the plaintext with a random nonce.
+func (c *AESGCM) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	b := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decrypt decrypts the ciphertext returned by Encrypt.
+func (c *AESGCM) Decrypt(ciphertext string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	n := c.aead.NonceSize()
+	if len(b) < n+c.aead.Overhead() {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, b[:n], b[n:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
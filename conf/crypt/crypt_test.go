@@ -0,0 +1,112 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypt
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestAESGCM(t *testing.T) {
+
+	_, err := NewAESGCM([]byte("short"))
+	assert.Error(t, err, "invalid key size 5")
+
+	key, err := GenerateKey()
+	assert.Nil(t, err)
+	b, err := base64.StdEncoding.DecodeString(key)
+	assert.Nil(t, err)
+	c, err := NewAESGCM(b)
+	assert.Nil(t, err)
+
+	s1, err := c.Encrypt("p@ss${word}")
+	assert.Nil(t, err)
+	s2, err := c.Encrypt("p@ss${word}")
+	assert.Nil(t, err)
+	assert.NotEqual(t, s1, s2)
+
+	s, err := c.Decrypt(s1)
+	assert.Nil(t, err)
+	assert.Equal(t, s, "p@ss${word}")
+
+	_, err = c.Decrypt(base64.StdEncoding.EncodeToString([]byte("x")))
+	assert.Error(t, err, "ciphertext too short")
+
+	other, err := NewAESGCM(make([]byte, 32))
+	assert.Nil(t, err)
+	_, err = other.Decrypt(s1)
+	assert.Error(t, err, "message authentication failed")
+}
+
+func TestLoadKey(t *testing.T) {
+	os.Clearenv()
+	_, err := LoadKey()
+	assert.True(t, errors.Is(err, ErrNoKey))
+
+	key, err := GenerateKey()
+	assert.Nil(t, err)
+	file := filepath.Join(t.TempDir(), "key")
+	assert.Nil(t, os.WriteFile(file, []byte(key+"\n"), 0600))
+	t.Setenv(KeyFileEnv, file)
+	b, err := LoadKey()
+	assert.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(b), key)
+
+	t.Setenv(KeyEnv, "!")
+	_, err = LoadKey()
+	assert.Error(t, err, "invalid encrypt key")
+}
+
+func TestDecryptProperties(t *testing.T) {
+
+	c, err := NewAESGCM(make([]byte, 16))
+	assert.Nil(t, err)
+	ciphertext, err := c.Encrypt("secret")
+	assert.Nil(t, err)
+
+	p := conf.New()
+	assert.Nil(t, p.Set("db.password", "{cipher}"+ciphertext))
+	assert.Nil(t, p.Set("db.dsn", "root:${db.password}@tcp"))
+
+	var s struct {
+		Password string `value:"${password}"`
+	}
+	err = p.Bind(&s, conf.Key("db"))
+	assert.Error(t, err, "property \"db.password\" is encrypted but no decryptor")
+
+	conf.SetDecryptor(c)
+	defer conf.SetDecryptor(nil)
+
+	assert.Nil(t, p.Bind(&s, conf.Key("db")))
+	assert.Equal(t, s.Password, "secret")
+	dsn, err := p.Resolve("${db.dsn}")
+	assert.Nil(t, err)
+	assert.Equal(t, dsn, "root:secret@tcp")
+
+	conf.SetCipherMarker("ENC:")
+	defer conf.SetCipherMarker("{cipher}")
+	assert.Nil(t, p.Set("db.token", "ENC:"+ciphertext))
+	token, err := p.Resolve("${db.token}")
+	assert.Nil(t, err)
+	assert.Equal(t, token, "secret")
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Decryptor decrypts the property values marked as encrypted, see SetDecryptor.
+type Decryptor interface {
+	Decrypt(ciphertext string) (string, error)
+}
+
+var (
+	decryptor    Decryptor
+	cipherMarker = "{cipher}"
+)
+
+// SetDecryptor sets the Decryptor of the property values starting with the cipher marker,
+// which are decrypted transparently when they are resolved or bound.
+//
+//	db.password={cipher}3q2+7w...
+func SetDecryptor(d Decryptor) {
+	decryptor = d
+}
+
+// HasDecryptor returns whether a Decryptor is set.
+func HasDecryptor() bool {
+	return decryptor != nil
+}
+
+// SetCipherMarker sets the prefix marking the encrypted property values, `{cipher}` by default.
+func SetCipherMarker(marker string) {
+	cipherMarker = marker
+}
+
+// isEncrypted returns whether the value starts with the cipher marker.
+func isEncrypted(val string) bool {
+	return cipherMarker != "" && strings.HasPrefix(val, cipherMarker)
+}
+
+// decrypt decrypts the encrypted value of the key.
+func decrypt(key, val string) (string, error) {
+	if decryptor == nil {
+		return "", fmt.Errorf("property %q is encrypted but no decryptor", key)
+	}
+	s, err := decryptor.Decrypt(strings.TrimPrefix(val, cipherMarker))
+	if err != nil {
+		return "", fmt.Errorf("decrypt property %q error: %w", key, err)
+	}
+	return s, nil
+}
@@ -19,6 +19,8 @@ package internal
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"unicode"
 
 	"go-spring.dev/spring/internal/utils"
 )
@@ -55,10 +57,11 @@ func (t *treeNode) Copy() *treeNode {
 	return r
 }
 
-// Storage stores data in the properties format.
+// Storage stores data in the properties format, with the origins of the keys.
 type Storage struct {
-	tree *treeNode
-	data map[string]string
+	tree    *treeNode
+	data    map[string]string
+	origins map[string]string
 }
 
 // NewStorage returns a new *Storage object.
@@ -68,7 +71,8 @@ func NewStorage() *Storage {
 			node: nodeTypeMap,
 			data: make(map[string]*treeNode),
 		},
-		data: make(map[string]string),
+		data:    make(map[string]string),
+		origins: make(map[string]string),
 	}
 }
 
@@ -78,9 +82,14 @@ func (s *Storage) Copy() *Storage {
 	if nil == data {
 		data = make(map[string]string)
 	}
+	origins := make(map[string]string, len(s.origins))
+	for k, v := range s.origins {
+		origins[k] = v
+	}
 	return &Storage{
-		tree: s.tree.Copy(),
-		data: data,
+		tree:    s.tree.Copy(),
+		data:    data,
+		origins: origins,
 	}
 }
 
@@ -163,14 +172,97 @@ func (s *Storage) Has(key string) bool {
 	return true
 }
 
+// Lookup returns the stored key that equals the key in the relaxed form, which ignores
+// the case, `-` and `_` of the map keys, such as `max-conns`, `maxConns` and `MAX_CONNS`,
+// an exact match takes precedence.
+func (s *Storage) Lookup(key string) (string, bool) {
+	if s.Has(key) {
+		return key, true
+	}
+	path, err := SplitPath(key)
+	if err != nil || len(path) == 0 {
+		return "", false
+	}
+	tree := s.tree
+	for i, node := range path {
+		switch tree.node {
+		case nodeTypeArray:
+			if node.Type != PathTypeIndex {
+				return "", false
+			}
+		case nodeTypeMap:
+			if node.Type != PathTypeKey {
+				return "", false
+			}
+		default:
+			return "", false
+		}
+		m := tree.data.(map[string]*treeNode)
+		v, ok := m[node.Elem]
+		if !ok && node.Type == PathTypeKey {
+			elem := relaxed(node.Elem)
+			for _, k := range utils.SortedKeys(m) {
+				if relaxed(k) == elem {
+					path[i].Elem, v, ok = k, m[k], true
+					break
+				}
+			}
+		}
+		if !ok {
+			return "", false
+		}
+		tree = v
+	}
+	return JoinPath(path), true
+}
+
+// relaxed returns the lower case key without `-` and `_`.
+func relaxed(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
+
 // Get returns the key's value.
 func (s *Storage) Get(key string) string {
 	val, _ := s.data[key]
 	return val
 }
 
-// Set stores the key and its value.
-func (s *Storage) Set(key, val string) error {
+// Origin returns where the key comes from, the origin of a list or a map is that of
+// its first element, and an element without origin has the origin of its nearest parent.
+func (s *Storage) Origin(key string) string {
+	if origin, ok := s.origins[key]; ok {
+		return origin
+	}
+	first := ""
+	for k := range s.origins {
+		if strings.HasPrefix(k, key+".") || strings.HasPrefix(k, key+"[") {
+			if first == "" || k < first {
+				first = k
+			}
+		}
+	}
+	if first != "" {
+		return s.origins[first]
+	}
+	path, err := SplitPath(key)
+	if err != nil {
+		return ""
+	}
+	for i := len(path) - 1; i > 0; i-- {
+		if origin, ok := s.origins[JoinPath(path[:i])]; ok {
+			return origin
+		}
+	}
+	return ""
+}
+
+// Set stores the key, its value and its origin, an empty origin means it's unknown.
+func (s *Storage) Set(key, val, origin string) error {
 	tree, err := s.merge(key, val)
 	if err != nil {
 		return err
@@ -178,6 +270,11 @@ func (s *Storage) Set(key, val string) error {
 	switch tree.node {
 	case nodeTypeNil, nodeTypeValue:
 		s.data[key] = val
+		if origin != "" {
+			s.origins[key] = origin
+		} else {
+			delete(s.origins, key)
+		}
 	}
 	return nil
 }
@@ -201,6 +298,7 @@ func (s *Storage) merge(key, val string) (*treeNode, error) {
 		v, ok := m[pathNode.Elem]
 		if v != nil && v.node == nodeTypeNil {
 			delete(s.data, JoinPath(path[:i+1]))
+			delete(s.origins, JoinPath(path[:i+1]))
 		}
 		if !ok || v.node == nodeTypeNil {
 			if i < len(path)-1 {
@@ -38,29 +38,29 @@ func TestStorage(t *testing.T) {
 		subKeys, err = s.SubKeys("m[b]", false)
 		assert.Error(t, err, "invalid key 'm\\[b]'")
 
-		err = s.Set("m[b]", "123")
+		err = s.Set("m[b]", "123", "")
 		assert.Error(t, err, "invalid key 'm\\[b]'")
 
-		err = s.Set("[0].x", "123")
+		err = s.Set("[0].x", "123", "")
 		assert.Error(t, err, "invalid key '\\[0].x'")
 	})
 
 	t.Run("storage keys", func(t *testing.T) {
 		s := NewStorage()
 
-		err := s.Set("a.b[0].c", "")
+		err := s.Set("a.b[0].c", "", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"a.b[0].c"})
 
-		err = s.Set("a.b[0].c[0]", "123")
+		err = s.Set("a.b[0].c[0]", "123", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"a.b[0].c[0]"})
 
-		err = s.Set("a.b[0].d", "")
+		err = s.Set("a.b[0].d", "", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"a.b[0].c[0]", "a.b[0].d"})
 
-		err = s.Set("a.b[0].d.e", "123")
+		err = s.Set("a.b[0].d.e", "123", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"a.b[0].c[0]", "a.b[0].d.e"})
 	})
@@ -69,39 +69,39 @@ func TestStorage(t *testing.T) {
 		s := NewStorage()
 		assert.False(t, s.Has("a"))
 
-		err := s.Set("a", "b")
+		err := s.Set("a", "b", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("a"))
 		assert.Equal(t, s.Get("a"), "b")
 
-		err = s.Set("a[0]", "x")
+		err = s.Set("a[0]", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\[0]' wants other type")
-		err = s.Set("a.y", "x")
+		err = s.Set("a.y", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\.y' wants other type")
 		assert.Equal(t, s.Keys(), []string{"a"})
 
 		_, err = s.SubKeys("a", false)
 		assert.Error(t, err, "property 'a' is value")
 
-		err = s.Set("a", "c")
+		err = s.Set("a", "c", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("a"))
 		assert.Equal(t, s.Get("a"), "c")
 
-		err = s.Set("a[0]", "x")
+		err = s.Set("a[0]", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\[0]' wants other type")
-		err = s.Set("a.y", "x")
+		err = s.Set("a.y", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\.y' wants other type")
 		assert.Equal(t, s.Keys(), []string{"a"})
 
-		err = s.Set("a", "c")
+		err = s.Set("a", "c", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("a"))
 		assert.Equal(t, s.Get("a"), "c")
 
-		err = s.Set("a[0]", "x")
+		err = s.Set("a[0]", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\[0]' wants other type")
-		err = s.Set("a.y", "x")
+		err = s.Set("a.y", "x", "")
 		assert.Error(t, err, "property 'a' is a value but 'a\\.y' wants other type")
 		assert.Equal(t, s.Keys(), []string{"a"})
 
@@ -113,15 +113,15 @@ func TestStorage(t *testing.T) {
 		s := NewStorage()
 		assert.False(t, s.Has("m.x"))
 
-		err := s.Set("m.x", "y")
+		err := s.Set("m.x", "y", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("m"))
 		assert.True(t, s.Has("m.x"))
 		assert.Equal(t, s.Get("m.x"), "y")
 
-		err = s.Set("m", "w")
+		err = s.Set("m", "w", "")
 		assert.Error(t, err, "property 'm' is a map but 'm' wants other type")
-		err = s.Set("m[0]", "f")
+		err = s.Set("m[0]", "f", "")
 		assert.Error(t, err, "property 'm' is a map but 'm\\[0]' wants other type")
 		assert.Equal(t, s.Keys(), []string{"m.x"})
 
@@ -129,16 +129,16 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"x"})
 
-		err = s.Set("m.x", "z")
+		err = s.Set("m.x", "z", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("m"))
 		assert.True(t, s.Has("m.x"))
 		assert.False(t, s.Has("m[0]"))
 		assert.Equal(t, s.Get("m.x"), "z")
 
-		err = s.Set("m", "w")
+		err = s.Set("m", "w", "")
 		assert.Error(t, err, "property 'm' is a map but 'm' wants other type")
-		err = s.Set("m[0]", "f")
+		err = s.Set("m[0]", "f", "")
 		assert.Error(t, err, "property 'm' is a map but 'm\\[0]' wants other type")
 		assert.Equal(t, s.Keys(), []string{"m.x"})
 
@@ -146,18 +146,18 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"x"})
 
-		err = s.Set("m.t", "q")
+		err = s.Set("m.t", "q", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("m"))
 		assert.True(t, s.Has("m.t"))
 		assert.Equal(t, s.Get("m.x"), "z")
 		assert.Equal(t, s.Get("m.t"), "q")
 
-		err = s.Set("m", "w")
+		err = s.Set("m", "w", "")
 		assert.Error(t, err, "property 'm' is a map but 'm' wants other type")
-		err = s.Set("m[0]", "f")
+		err = s.Set("m[0]", "f", "")
 		assert.Error(t, err, "property 'm' is a map but 'm\\[0]' wants other type")
-		err = s.Set("m.t[0]", "f")
+		err = s.Set("m.t[0]", "f", "")
 		assert.Error(t, err, "property 'm.t' is a value but 'm.t\\[0]' wants other type")
 		assert.Equal(t, s.Keys(), []string{"m.t", "m.x"})
 
@@ -165,7 +165,7 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"t", "x"})
 
-		err = s.Set("m", "")
+		err = s.Set("m", "", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"m.t", "m.x"})
 
@@ -177,15 +177,15 @@ func TestStorage(t *testing.T) {
 		s := NewStorage()
 		assert.False(t, s.Has("s[0]"))
 
-		err := s.Set("s[0]", "p")
+		err := s.Set("s[0]", "p", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("s"))
 		assert.True(t, s.Has("s[0]"))
 		assert.Equal(t, s.Get("s[0]"), "p")
 
-		err = s.Set("s", "w")
+		err = s.Set("s", "w", "")
 		assert.Error(t, err, "property 's' is an array but 's' wants other type")
-		err = s.Set("s.x", "f")
+		err = s.Set("s.x", "f", "")
 		assert.Error(t, err, "property 's' is an array but 's\\.x' wants other type")
 		assert.Equal(t, s.Keys(), []string{"s[0]"})
 
@@ -193,16 +193,16 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"0"})
 
-		err = s.Set("s[0]", "q")
+		err = s.Set("s[0]", "q", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("s"))
 		assert.True(t, s.Has("s[0]"))
 		assert.False(t, s.Has("s.0"))
 		assert.Equal(t, s.Get("s[0]"), "q")
 
-		err = s.Set("s", "w")
+		err = s.Set("s", "w", "")
 		assert.Error(t, err, "property 's' is an array but 's' wants other type")
-		err = s.Set("s.x", "f")
+		err = s.Set("s.x", "f", "")
 		assert.Error(t, err, "property 's' is an array but 's\\.x' wants other type")
 		assert.Equal(t, s.Keys(), []string{"s[0]"})
 
@@ -210,16 +210,16 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"0"})
 
-		err = s.Set("s[1]", "o")
+		err = s.Set("s[1]", "o", "")
 		assert.Nil(t, err)
 		assert.True(t, s.Has("s"))
 		assert.True(t, s.Has("s[1]"))
 		assert.Equal(t, s.Get("s[0]"), "q")
 		assert.Equal(t, s.Get("s[1]"), "o")
 
-		err = s.Set("s", "w")
+		err = s.Set("s", "w", "")
 		assert.Error(t, err, "property 's' is an array but 's' wants other type")
-		err = s.Set("s.x", "f")
+		err = s.Set("s.x", "f", "")
 		assert.Error(t, err, "property 's' is an array but 's\\.x' wants other type")
 		assert.Equal(t, s.Keys(), []string{"s[0]", "s[1]"})
 
@@ -227,7 +227,7 @@ func TestStorage(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, subKeys, []string{"0", "1"})
 
-		err = s.Set("s", "")
+		err = s.Set("s", "", "")
 		assert.Nil(t, err)
 		assert.Equal(t, s.Keys(), []string{"s[0]", "s[1]"})
 
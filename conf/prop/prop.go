@@ -16,7 +16,11 @@
 
 package prop
 
-import "github.com/magiconair/properties"
+import (
+	"strings"
+
+	"github.com/magiconair/properties"
+)
 
 // Read parses []byte in the properties format into map.
 func Read(b []byte) (map[string]interface{}, error) {
@@ -31,3 +35,25 @@ func Read(b []byte) (map[string]interface{}, error) {
 	}
 	return ret, nil
 }
+
+// Lines returns the line numbers of the keys, starting from 1.
+func Lines(b []byte) map[string]int {
+	m := make(map[string]int)
+	continued := false
+	for i, line := range strings.Split(string(b), "\n") {
+		s := strings.TrimSpace(line)
+		if continued {
+			continued = strings.HasSuffix(s, `\`)
+			continue
+		}
+		if s == "" || s[0] == '#' || s[0] == '!' {
+			continue
+		}
+		continued = strings.HasSuffix(s, `\`)
+		if j := strings.IndexAny(s, "=: \t"); j >= 0 {
+			s = s[:j]
+		}
+		m[s] = i + 1
+	}
+	return m
+}
@@ -117,3 +117,14 @@ func TestRead(t *testing.T) {
 		})
 	})
 }
+
+func TestLines(t *testing.T) {
+	str := "# comment\na.b=1\n\n! comment\nc : 2\nd long \\\n  value\ne[0]=3\n"
+	m := Lines([]byte(str))
+	assert.Equal(t, m, map[string]int{
+		"a.b":  2,
+		"c":    5,
+		"d":    6,
+		"e[0]": 8,
+	})
+}
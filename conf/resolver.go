@@ -0,0 +1,152 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conf
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Resolver resolves the references of its prefix, the argument is the text after the
+// prefix, such as `HOME` of `${env:HOME}` or `1,10` of `${random.int(1,10)}`.
+type Resolver func(arg string) (string, error)
+
+var resolvers = map[string]Resolver{}
+
+func init() {
+	RegisterResolver("env", resolveEnv)
+	RegisterResolver("file", resolveFile)
+	RegisterResolver("base64", resolveBase64)
+	RegisterResolver("random.uuid", resolveRandomUUID)
+	RegisterResolver("random.int", resolveRandomInt)
+}
+
+// RegisterResolver registers a Resolver of the prefix, the references are `${prefix:arg}`,
+// `${prefix(arg)}` or `${prefix}`, and the default value is used when the Resolver fails,
+// such as `${env:HOME:=/root}`. A property named as the prefix takes precedence over the
+// `${prefix}` form, so `${env}` is still the property `env`.
+func RegisterResolver(prefix string, fn Resolver) {
+	resolvers[prefix] = fn
+}
+
+// RemoveResolver removes a Resolver by its prefix, only for unit testing.
+func RemoveResolver(prefix string) {
+	delete(resolvers, prefix)
+}
+
+// lookupResolver returns the Resolver of the key and its argument, bare is true for the
+// `${prefix}` form, which is used only when there is no such property.
+func lookupResolver(key string) (fn Resolver, arg string, bare bool, ok bool) {
+	if i := strings.IndexByte(key, '('); i > 0 && strings.HasSuffix(key, ")") {
+		if fn, ok = resolvers[key[:i]]; ok {
+			return fn, key[i+1 : len(key)-1], false, true
+		}
+	}
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		if fn, ok = resolvers[key[:i]]; ok {
+			return fn, key[i+1:], false, true
+		}
+	}
+	if fn, ok = resolvers[key]; ok {
+		return fn, "", true, true
+	}
+	return nil, "", false, false
+}
+
+// resolveBy resolves the reference by the Resolver, the argument may contain references.
+func resolveBy(p *Properties, param BindParam, fn Resolver, arg string) (string, error) {
+	arg, err := resolveString(p, arg)
+	if err != nil {
+		return "", err
+	}
+	val, err := fn(arg)
+	if err != nil {
+		if param.Tag.HasDef {
+			return resolveString(p, param.Tag.Def)
+		}
+		return "", fmt.Errorf("resolve %q error: %w", param.Tag.Key, err)
+	}
+	return val, nil
+}
+
+func resolveEnv(arg string) (string, error) {
+	if val, ok := os.LookupEnv(arg); ok {
+		return val, nil
+	}
+	return "", fmt.Errorf("env %q: %w", arg, errNotExist)
+}
+
+// resolveFile returns the content of the file without the trailing newlines.
+func resolveFile(arg string) (string, error) {
+	b, err := os.ReadFile(arg)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func resolveBase64(arg string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(arg)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func resolveRandomUUID(arg string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// resolveRandomInt returns a random int in [min, max) of `random.int(min,max)`, or a
+// non-negative random int of `random.int`.
+func resolveRandomInt(arg string) (string, error) {
+	var min, max int64 = 0, math.MaxInt32
+	if arg != "" {
+		ss := strings.Split(arg, ",")
+		if len(ss) != 2 {
+			return "", fmt.Errorf("random.int(%s): %w", arg, errInvalidSyntax)
+		}
+		var err error
+		if min, err = strconv.ParseInt(strings.TrimSpace(ss[0]), 10, 64); err != nil {
+			return "", err
+		}
+		if max, err = strconv.ParseInt(strings.TrimSpace(ss[1]), 10, 64); err != nil {
+			return "", err
+		}
+		if min >= max {
+			return "", errors.New("random.int max should be greater than min")
+		}
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(max-min))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(min+n.Int64(), 10), nil
+}
@@ -17,6 +17,8 @@
 package toml
 
 import (
+	"fmt"
+
 	"github.com/pelletier/go-toml"
 )
 
@@ -28,3 +30,33 @@ func Read(b []byte) (map[string]interface{}, error) {
 	}
 	return tree.ToMap(), nil
 }
+
+// Lines returns the line numbers of the keys, the keys are flattened like `a.b[0].c`.
+func Lines(b []byte) map[string]int {
+	m := make(map[string]int)
+	if tree, err := toml.LoadBytes(b); err == nil {
+		lines(tree, "", m)
+	}
+	return m
+}
+
+func lines(tree *toml.Tree, prefix string, m map[string]int) {
+	for _, k := range tree.Keys() {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		path := []string{k}
+		m[key] = tree.GetPositionPath(path).Line
+		switch v := tree.GetPath(path).(type) {
+		case *toml.Tree:
+			lines(v, key, m)
+		case []*toml.Tree:
+			for i, e := range v {
+				s := fmt.Sprintf("%s[%d]", key, i)
+				m[s] = e.Position().Line
+				lines(e, s, m)
+			}
+		}
+	}
+}
@@ -146,3 +146,26 @@ func TestRead(t *testing.T) {
 		})
 	})
 }
+
+func TestLines(t *testing.T) {
+	str := `
+a = 1
+list = [1, 2]
+
+[http]
+addr = ":8080"
+
+[[db]]
+name = "a"
+
+[[db]]
+name = "b"
+`
+	m := Lines([]byte(str))
+	assert.Equal(t, m["a"], 2)
+	assert.Equal(t, m["list"], 3)
+	assert.Equal(t, m["http"], 5)
+	assert.Equal(t, m["http.addr"], 6)
+	assert.Equal(t, m["db[0].name"], 9)
+	assert.Equal(t, m["db[1].name"], 12)
+}
@@ -17,6 +17,9 @@
 package yaml
 
 import (
+	"fmt"
+	"strings"
+
 	"gopkg.in/yaml.v2"
 )
 
@@ -29,3 +32,115 @@ func Read(b []byte) (map[string]interface{}, error) {
 	}
 	return m, nil
 }
+
+// Lines returns the line numbers of the keys, the keys are flattened like `a.b[0].c`.
+// It recognizes the block mappings and sequences, a flow collection or a multi-line
+// scalar has the line of its key.
+func Lines(b []byte) map[string]int {
+
+	type frame struct {
+		indent int
+		key    string
+		item   bool // a sequence item
+		next   int  // the index of the next item
+	}
+
+	var (
+		m     = make(map[string]int)
+		stack []*frame
+		block = -1 // the indent of the key of a block scalar
+	)
+
+	for n, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimRight(line, "\r \t")
+		s := strings.TrimLeft(line, " ")
+		indent := len(line) - len(s)
+		if block >= 0 {
+			if s == "" || indent > block {
+				continue
+			}
+			block = -1
+		}
+		if s == "" || s[0] == '#' || s == "---" || s == "..." {
+			continue
+		}
+		for {
+			if s == "-" || strings.HasPrefix(s, "- ") {
+				for len(stack) > 0 {
+					top := stack[len(stack)-1]
+					if top.indent < indent || (top.indent == indent && !top.item) {
+						break
+					}
+					stack = stack[:len(stack)-1]
+				}
+				if len(stack) == 0 {
+					return m // the sequence at root isn't a property
+				}
+				parent := stack[len(stack)-1]
+				key := fmt.Sprintf("%s[%d]", parent.key, parent.next)
+				parent.next++
+				m[key] = n + 1
+				stack = append(stack, &frame{indent: indent, key: key, item: true})
+				rest := strings.TrimLeft(s[1:], " ")
+				if rest == "" || rest[0] == '#' {
+					break
+				}
+				indent += len(s) - len(rest)
+				s = rest
+				continue
+			}
+			k, v, ok := splitKey(s)
+			if !ok {
+				break
+			}
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+			key := k
+			if len(stack) > 0 {
+				key = stack[len(stack)-1].key + "." + k
+			}
+			m[key] = n + 1
+			switch {
+			case v == "" || v[0] == '#':
+				stack = append(stack, &frame{indent: indent, key: key})
+			case v[0] == '|' || v[0] == '>':
+				block = indent
+			}
+			break
+		}
+	}
+	return m
+}
+
+// splitKey splits a mapping line into the key and the value.
+func splitKey(s string) (key, value string, ok bool) {
+	if s[0] == '"' || s[0] == '\'' {
+		j := strings.IndexByte(s[1:], s[0])
+		if j < 0 || !strings.HasPrefix(s[j+2:], ":") {
+			return "", "", false
+		}
+		key, s = s[1:j+1], s[j+3:]
+	} else {
+		j := 0
+		for {
+			i := strings.IndexByte(s[j:], ':')
+			if i < 0 {
+				return "", "", false
+			}
+			j += i
+			if j+1 == len(s) || s[j+1] == ' ' || s[j+1] == '\t' {
+				break
+			}
+			j++
+		}
+		if j == 0 {
+			return "", "", false
+		}
+		key, s = s[:j], s[j+1:]
+	}
+	if s != "" && s[0] != ' ' && s[0] != '\t' {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(s), true
+}
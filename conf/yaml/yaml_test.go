@@ -171,3 +171,42 @@ func TestRead(t *testing.T) {
 		})
 	})
 }
+
+func TestLines(t *testing.T) {
+	str := `
+# comment
+http:
+  addr: ":8080"
+  read-timeout: 3s
+  "quoted": 1
+db:
+  - username: root
+    password: "p@ss:word"
+  -
+    username: admin
+list:
+- a
+- b
+text: |
+  key: not a key
+url: http://example.com
+`
+	m := Lines([]byte(str))
+	assert.Equal(t, m, map[string]int{
+		"http":              3,
+		"http.addr":         4,
+		"http.read-timeout": 5,
+		"http.quoted":       6,
+		"db":                7,
+		"db[0]":             8,
+		"db[0].username":    8,
+		"db[0].password":    9,
+		"db[1]":             10,
+		"db[1].username":    11,
+		"list":              12,
+		"list[0]":           13,
+		"list[1]":           14,
+		"text":              15,
+		"url":               17,
+	})
+}
@@ -22,9 +22,18 @@ import (
 	"sort"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"go-spring.dev/spring/conf"
 	"go-spring.dev/spring/internal/utils"
+	"go-spring.dev/spring/metrics"
+)
+
+var (
+	refreshTotal = metrics.Default.Counter("dync_refreshes_total",
+		"The number of refreshes of dynamic properties by result.", "result")
+	refreshSeconds = metrics.Default.Histogram("dync_refresh_seconds",
+		"The duration of refreshes of dynamic properties.", nil).With()
 )
 
 // A Field represents a refreshable struct field.
@@ -65,6 +74,11 @@ func (p *Properties) Get(key string, opts ...conf.GetOption) string {
 	return p.load().Get(key, opts...)
 }
 
+// Origin returns where the property comes from.
+func (p *Properties) Origin(key string) string {
+	return p.load().Origin(key)
+}
+
 // Resolve resolves string value.
 func (p *Properties) Resolve(s string) (string, error) {
 	return p.load().Resolve(s)
@@ -76,9 +90,9 @@ func (p *Properties) Bind(i interface{}, args ...conf.BindArg) error {
 }
 
 // Set refresh properties value by key.
-func (p *Properties) Set(key, value string) error {
+func (p *Properties) Set(key, value string, opts ...conf.SetOption) error {
 	prop := p.load().Copy()
-	if err := prop.Set(key, value); nil != err {
+	if err := prop.Set(key, value, opts...); nil != err {
 		return err
 	}
 	p.value.Store(prop)
@@ -91,7 +105,7 @@ func (p *Properties) Remove(key string) error {
 	coped := conf.New()
 	for _, k := range prop.Keys() {
 		if k != key {
-			if err := coped.Set(k, prop.Get(k)); nil != err {
+			if err := coped.Set(k, prop.Get(k), conf.From(prop.Origin(k))); nil != err {
 				return err
 			}
 		}
@@ -104,6 +118,16 @@ func (p *Properties) Remove(key string) error {
 // Refresh refreshes new Properties atomically.
 func (p *Properties) Refresh(prop *conf.Properties) (err error) {
 
+	start := time.Now()
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		refreshTotal.With(result).Inc()
+		refreshSeconds.Observe(time.Since(start).Seconds())
+	}()
+
 	old := p.load()
 	p.value.Store(prop)
 
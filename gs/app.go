@@ -18,16 +18,20 @@ package gs
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/signal"
 	"reflect"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
+	"time"
 	"unicode/utf8"
 
 	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/conf/crypt"
 	"go-spring.dev/spring/gs/arg"
 )
 
@@ -39,71 +43,281 @@ type AppEvent interface {
 
 // App Ioc App
 type App struct {
-	container *container
-	exitChan  chan struct{}
+	container    *container
+	availability *Availability
+	exitChan     chan struct{}
+	exitOnce     sync.Once
+	args         []string
+	arguments    *ApplicationArguments
+	command      *activeCommand
+
+	locator       ResourceLocator
+	envPrefix     string
+	props         *conf.Properties
+	base          *conf.Properties // the properties set by Property, see Reload
+	defaults      *conf.Properties // the properties added while refreshing
+	overrides     *conf.Properties // the properties changed while refreshing
+	noSignals     bool
+	noAutoConfigs bool
+
+	started    bool
+	signals    chan os.Signal
+	signalDone chan struct{}
+	runDone    chan struct{}
+	runErr     error
+	stopOnce   sync.Once
+	stopErr    error
+	cancel     context.CancelFunc
+}
+
+// AppOption configures an App.
+type AppOption func(app *App)
+
+// WithArgs sets the command line arguments of the App, without the program name,
+// the arguments of os.Args are used by default.
+func WithArgs(args []string) AppOption {
+	return func(app *App) {
+		app.args = args
+	}
+}
+
+// WithResourceLocator sets the locator of the configuration files.
+func WithResourceLocator(locator ResourceLocator) AppOption {
+	return func(app *App) {
+		app.locator = locator
+	}
+}
+
+// WithEnvPrefix sets the prefix of the environment variables loaded as properties, it's
+// EnvPrefix by default, an empty prefix loads all environment variables.
+func WithEnvPrefix(prefix string) AppOption {
+	return func(app *App) {
+		app.envPrefix = prefix
+	}
+}
+
+// WithProperties sets the properties of the App instead of loading them from the
+// configuration files, the environment variables and the command line arguments.
+func WithProperties(p *conf.Properties) AppOption {
+	return func(app *App) {
+		app.props = p
+	}
+}
+
+// WithoutSignals disables the shutdown of the App by SIGINT and SIGTERM, which suits
+// the App embedded in another program or driven by tests.
+func WithoutSignals() AppOption {
+	return func(app *App) {
+		app.noSignals = true
+	}
+}
+
+// WithoutAutoConfigs disables the auto-configurations registered by the imported starters,
+// the App installs the modules it needs explicitly, see AutoConfigModule.
+func WithoutAutoConfigs() AppOption {
+	return func(app *App) {
+		app.noAutoConfigs = true
+	}
 }
 
 // NewApp make a new App
-func NewApp() *App {
-	return &App{
-		container: New().(*container),
-		exitChan:  make(chan struct{}),
+func NewApp(opts ...AppOption) *App {
+	app := &App{
+		container:    New().(*container),
+		availability: NewAvailability(),
+		exitChan:     make(chan struct{}),
+		args:         os.Args[1:],
+		locator:      new(FileResourceLocator),
+		envPrefix:    EnvPrefix,
+	}
+	for _, opt := range opts {
+		opt(app)
 	}
+	if !app.noAutoConfigs {
+		app.container.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			return applyAutoConfigs(r, uninstalled(autoConfigs, app.container.installed))
+		}))
+	}
+	app.container.PostProcessor(&commandPostProcessor{app: app, output: os.Stderr})
+	app.container.Object(app.availability)
+	app.container.Object(&livenessIndicator{app.availability}).Name("livenessState").
+		Label("liveness").Export((*HealthIndicator)(nil))
+	app.container.Object(&readinessIndicator{app.availability}).Name("readinessState").
+		Label("readiness").Export((*HealthIndicator)(nil))
+	return app
 }
 
-// Run start app.
+// Run start app with the command line arguments of os.Args or WithArgs, it returns when
+// the app is shut down, with the error of the runner failed if any, see Runner.
 func (app *App) Run(resourceLocator ...ResourceLocator) error {
-	var locator ResourceLocator = new(FileResourceLocator)
 	if len(resourceLocator) > 0 && resourceLocator[0] != nil {
-		locator = resourceLocator[0]
+		app.locator = resourceLocator[0]
 	}
 
-	return app.run(locator)
+	if err := app.Start(context.Background()); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	<-app.exitChan
+	return app.Stop(context.Background())
 }
 
-func (app *App) run(resourceLocator ResourceLocator) error {
+// RunWithArgs start app with the command line arguments, without the program name,
+// see ApplicationArguments.
+func (app *App) RunWithArgs(args []string, resourceLocator ...ResourceLocator) error {
+	app.args = args
+	return app.Run(resourceLocator...)
+}
 
-	e := NewAppConfiguration(resourceLocator)
+// Start loads the properties, refreshes the container and starts the app, it returns
+// once the app started, Stop or Shutdown stops the app.
+func (app *App) Start(ctx context.Context) error {
 
-	if err := e.Load(app.container.props); nil != err {
+	if app.started {
+		return errors.New("app already started")
+	}
+	app.started = true
+
+	arguments, err := ParseArguments(app.args)
+	if err != nil {
 		return err
 	}
+	app.arguments = arguments
+	app.container.Object(arguments)
+
+	// The encrypted properties are decrypted by the key of the environment.
+	if !conf.HasDecryptor() {
+		d, err := crypt.FromEnv()
+		if err == nil {
+			conf.SetDecryptor(d)
+		} else if !errors.Is(err, crypt.ErrNoKey) {
+			return err
+		}
+	}
+
+	app.base = app.container.props.Copy()
+	if app.props != nil {
+		for _, key := range app.props.Keys() {
+			if err = app.container.props.Set(key, app.props.Get(key), conf.From(app.props.Origin(key))); err != nil {
+				return err
+			}
+		}
+	} else {
+		e := NewAppConfiguration(app.locator)
+		e.args = app.args
+		e.envPrefix = app.envPrefix
+		if err = e.Load(app.container.props); nil != err {
+			return err
+		}
+	}
 
 	if showBanner, _ := strconv.ParseBool(app.container.props.Get("spring.config.banner", conf.Def("true"))); showBanner {
 		app.printBanner(app.getBanner(app.container.props))
 	}
 
-	if err := app.container.p.Refresh(app.container.props); nil != err {
+	if err = app.container.p.Refresh(app.container.props); nil != err {
 		return err
 	}
 
-	if err := app.container.refresh(true); err != nil {
+	if err = ctx.Err(); err != nil {
 		return err
 	}
 
+	loaded := app.container.props
+	if err = app.container.refresh(true); err != nil {
+		return err
+	}
+	app.defaults, app.overrides = refreshedProperties(loaded, app.container.p)
+
 	var logger = GetLogger()
 
+	for _, bean := range app.container.Dependencies(true) {
+		if l, ok := bean.Value().Interface().(AvailabilityListener); ok {
+			app.availability.Listen(l)
+		}
+	}
+
 	app.onAppStart(app.container)
 
+	app.availability.SetReadiness(ReadinessAccepting)
 	logger.Info("application started successfully")
 
-	// Responding to the Ctrl+C and kill commands in the console.
+	// Responding to the Ctrl+C and kill commands in the console, and the other signals.
+	if !app.noSignals {
+		app.handleSignals()
+	}
+
+	var batch bool
+	if err = app.container.p.Bind(&batch, conf.Key("spring.app.batch:=false")); err != nil {
+		logger.Error("invalid batch mode", slog.Any("err", err))
+	}
+
+	// The runners are canceled when the application is shutting down.
+	runCtx, runCancel := context.WithCancel(WithContext(app.container.Context(), app.container))
+	app.cancel = runCancel
+	app.runDone = make(chan struct{})
 	go func() {
-		ch := make(chan os.Signal, 1)
-		signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-		sig := <-ch
-		app.Shutdown(fmt.Sprintf("signal %v", sig))
+		defer close(app.runDone)
+		app.runErr = runRunners(runCtx, app.runners(), arguments.Positional())
+		if app.runErr == nil && app.command != nil {
+			app.runErr = app.runCommand(runCtx)
+		}
+		if app.runErr != nil {
+			logger.Error("runner failed", slog.Any("err", app.runErr))
+			app.Shutdown("runner failed")
+		} else if batch || app.command != nil {
+			app.Shutdown("runners completed")
+		}
 	}()
+	return nil
+}
 
-	<-app.exitChan
+// Stop shuts down the app started by Start and waits for it to stop, the drain delay
+// is cut short when ctx is done. It returns the error of the runner failed if any.
+func (app *App) Stop(ctx context.Context) error {
+	if app.runDone == nil {
+		return errors.New("app not started")
+	}
+	app.Shutdown("stop")
+	app.stopOnce.Do(func() {
+		app.stopErr = app.stop(ctx)
+	})
+	return app.stopErr
+}
+
+func (app *App) stop(ctx context.Context) error {
+	logger := GetLogger()
+
+	app.cancel()
+	<-app.runDone
+
+	// Waiting for the load balancers to stop routing traffic after readiness changed.
+	var drainDelay time.Duration
+	if err := app.container.p.Bind(&drainDelay, conf.Key("spring.app.drain-delay:=0s")); err != nil {
+		logger.Error("invalid drain delay", slog.Any("err", err))
+	}
+	if drainDelay > 0 {
+		logger.Info("draining before stop", slog.Duration("delay", drainDelay))
+		select {
+		case <-time.After(drainDelay):
+		case <-ctx.Done():
+		}
+	}
 
-	app.onAppStop(app.container)
+	app.onAppStop(ctx, app.container)
 
 	app.container.Close()
 
+	app.stopSignals()
+
 	logger.Info("application exited")
 
-	return nil
+	if errors.Is(app.runErr, context.Canceled) {
+		return nil
+	}
+	return app.runErr
 }
 
 func (app *App) onAppStart(ctx Context) {
@@ -117,8 +331,8 @@ func (app *App) onAppStart(ctx Context) {
 	}
 }
 
-func (app *App) onAppStop(ctx Context) {
-	gsCtx := WithContext(context.Background(), ctx)
+func (app *App) onAppStop(parent context.Context, ctx Context) {
+	gsCtx := WithContext(parent, ctx)
 	for _, bean := range app.container.Dependencies(false) {
 		x := bean.Value().Interface()
 
@@ -162,16 +376,14 @@ func (app *App) printBanner(banner string) {
 	fmt.Println(banner)
 }
 
-// Shutdown close application.
+// Shutdown close application, it's safe to be called concurrently.
 func (app *App) Shutdown(msg ...string) {
-	select {
-	case <-app.exitChan:
-		// app already closed
-	default:
+	app.exitOnce.Do(func() {
 		var logger = GetLogger()
 		logger.Info(fmt.Sprintf("program will exit %s", strings.Join(msg, ", ")))
+		app.availability.SetReadiness(ReadinessRefusing)
 		close(app.exitChan)
-	}
+	})
 }
 
 // OnProperty binding a callback when the property key loaded.
@@ -181,7 +393,7 @@ func (app *App) OnProperty(key string, fn interface{}) {
 
 // Property set property key/value
 func (app *App) Property(key string, value interface{}) {
-	app.container.Property(key, value)
+	app.container.property(key, value, 2)
 }
 
 // Accept register bean to Ioc container.
@@ -208,12 +420,22 @@ func (app *App) Configuration(i interface{}) *BeanDefinition {
 	return app.container.Configuration(bd)
 }
 
+// Install install the modules and the modules they require to Ioc container.
+func (app *App) Install(modules ...*Module) {
+	app.container.Install(modules...)
+}
+
+// PostProcessor register a BeanDefinitionRegistryPostProcessor to Ioc container.
+func (app *App) PostProcessor(p BeanDefinitionRegistryPostProcessor) {
+	app.container.PostProcessor(p)
+}
+
 // AllowCircularReferences enable circular-references.
 func (app *App) AllowCircularReferences() {
 	app.container.AllowCircularReferences()
 }
 
 // Go start a goroutine managed by the IoC container.
-func (app *App) Go(fn func(ctx context.Context)) {
-	app.container.Go(fn)
+func (app *App) Go(fn func(ctx context.Context), opts ...GoOption) {
+	app.container.Go(fn, opts...)
 }
@@ -18,65 +18,252 @@ package gs
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"go-spring.dev/spring/conf"
 )
 
-// EnvPrefix environment variable prefix。
+// EnvPrefix is the default prefix of the environment variables loaded as properties,
+// see WithEnvPrefix.
 const EnvPrefix = "GS_"
 
-// loadCmdArgs 加载以 -D key=value 或者 -D key[=true] 形式传入的命令行参数。
-func loadCmdArgs(args []string, p *conf.Properties) error {
+// ApplicationArguments is the command line arguments of the application, it's a bean.
+// The arguments are parsed as follows:
+//
+//	-D key=value     sets the property, `-D key` sets it to true.
+//	--key=value      the option key, the options are bound into the properties.
+//	--key value      the option key, when the value isn't a command and doesn't start
+//	                 with `-` but a negative number.
+//	--key            the option key with the value true.
+//	--no-key         the option key with the value false.
+//	--               the arguments after it are positional.
+//
+// A repeated option is a list. The other arguments starting with `-` are ignored, and
+// the rest are positional.
+type ApplicationArguments struct {
+	args       []string
+	defines    [][2]string
+	names      []string
+	options    map[string][]string
+	positional []int // indexes of the positional arguments
+}
+
+// ParseArguments parses the command line arguments, without the program name.
+func ParseArguments(args []string) (*ApplicationArguments, error) {
+	return parseArguments(args, nil)
+}
+
+// parseArguments parses the command line arguments, an option doesn't take the next
+// argument as its value when it's a command, so `--verbose migrate` is the option
+// verbose of true and the command migrate.
+func parseArguments(args []string, commands map[string]Command) (*ApplicationArguments, error) {
+	a := &ApplicationArguments{
+		args:    args,
+		options: make(map[string][]string),
+	}
 	for i := 0; i < len(args); i++ {
 		s := args[i]
-		if s == "-D" {
+		switch {
+		case s == "--":
+			for i++; i < len(args); i++ {
+				a.positional = append(a.positional, i)
+			}
+		case s == "-D":
 			if i >= len(args)-1 {
-				return errors.New("cmd option -D needs arg")
+				return nil, errors.New("cmd option -D needs arg")
 			}
-			next := args[i+1]
-			ss := strings.SplitN(next, "=", 2)
-			if len(ss) == 1 {
-				ss = append(ss, "true")
+			i++
+			k, v, ok := strings.Cut(args[i], "=")
+			if !ok {
+				v = "true"
 			}
-			if err := p.Set(ss[0], ss[1]); err != nil {
-				return err
+			a.defines = append(a.defines, [2]string{k, v})
+		case strings.HasPrefix(s, "--"):
+			k, v, ok := strings.Cut(s[2:], "=")
+			if !ok {
+				switch {
+				case strings.HasPrefix(k, "no-"):
+					k, v = k[3:], "false"
+				case k != "help" && i < len(args)-1 && isOptionValue(args[i+1], commands):
+					i++
+					v = args[i]
+				default:
+					v = "true"
+				}
 			}
+			if k == "" {
+				return nil, fmt.Errorf("invalid option %q", s)
+			}
+			if _, ok = a.options[k]; !ok {
+				a.names = append(a.names, k)
+			}
+			a.options[k] = append(a.options[k], v)
+		case strings.HasPrefix(s, "-"):
+		default:
+			a.positional = append(a.positional, i)
 		}
 	}
-	return nil
+	return a, nil
+}
+
+// isOptionValue returns whether the argument after an option is its value.
+func isOptionValue(s string, commands map[string]Command) bool {
+	if _, ok := commands[s]; ok {
+		return false
+	}
+	if strings.HasPrefix(s, "-") {
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	}
+	return true
+}
+
+// Args returns the raw arguments.
+func (a *ApplicationArguments) Args() []string {
+	return a.args
+}
+
+// OptionNames returns the names of the options in the order they appear.
+func (a *ApplicationArguments) OptionNames() []string {
+	return a.names
+}
+
+// HasOption returns whether the option appears.
+func (a *ApplicationArguments) HasOption(name string) bool {
+	_, ok := a.options[name]
+	return ok
 }
 
-func loadSystemEnv(p *conf.Properties) error {
-	for _, env := range os.Environ() {
-		ss := strings.SplitN(env, "=", 2)
-		k, v := ss[0], ""
-		if len(ss) > 1 {
-			v = ss[1]
+// OptionValues returns the values of the option, nil if it doesn't appear.
+func (a *ApplicationArguments) OptionValues(name string) []string {
+	return a.options[name]
+}
+
+// Positional returns the positional arguments.
+func (a *ApplicationArguments) Positional() []string {
+	ret := make([]string, 0, len(a.positional))
+	for _, i := range a.positional {
+		ret = append(ret, a.args[i])
+	}
+	return ret
+}
+
+// bind sets the defines and the options into the properties, a repeated option is a list.
+func (a *ApplicationArguments) bind(p *conf.Properties) error {
+	for _, d := range a.defines {
+		if err := p.Set(d[0], d[1], conf.From("arg -D "+d[0])); err != nil {
+			return err
 		}
-		if strings.HasPrefix(k, EnvPrefix) {
-			propKey := strings.TrimPrefix(k, EnvPrefix)
-			propKey = strings.ReplaceAll(propKey, "_", ".")
-			propKey = strings.ToLower(propKey)
-			p.Set(propKey, v)
+	}
+	for _, name := range a.names {
+		err := a.bindOption(name, func(key, value string, opts ...conf.SetOption) error {
+			return p.Set(key, value, opts...)
+		})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func convertToEnv(key string) string {
-	if strings.Contains(key, ".") {
-		// replace '.' to '_'
-		key = strings.ReplaceAll(key, ".", "_")
+// bindOption sets the values of the option by the set function.
+func (a *ApplicationArguments) bindOption(name string, set func(key, value string, opts ...conf.SetOption) error) error {
+	values := a.options[name]
+	if len(values) == 1 {
+		return set(name, values[0], conf.From("arg --"+name))
 	}
+	for i, v := range values {
+		if err := set(fmt.Sprintf("%s[%d]", name, i), v, conf.From("arg --"+name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCmdArgs 加载命令行参数中的 -D key=value 和 --key=value 等形式的配置。
+func loadCmdArgs(args []string, p *conf.Properties) error {
+	a, err := ParseArguments(args)
+	if err != nil {
+		return err
+	}
+	return a.bind(p)
+}
+
+// loadSystemEnv loads the environment variables with the prefix in the order of their
+// names, see envToKey. A variable conflicting with a loaded one, such as `GS_LOG_LEVEL`
+// with `GS_LOG`, is logged and skipped.
+func loadSystemEnv(p *conf.Properties, prefix string) {
+	environ := os.Environ()
+	sort.Strings(environ)
+	for _, env := range environ {
+		k, v, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if err := p.Set(envToKey(strings.TrimPrefix(k, prefix)), v, conf.From("env "+k)); err != nil {
+			GetLogger().Warn("skip conflicting env", slog.String("env", k), slog.Any("err", err))
+		}
+	}
+}
 
-	// upper case
-	key = strings.ToUpper(key)
+// envToKey converts the environment variable name without prefix to the property key,
+// `_` separates the keys, a number is a list index, and the keys are lower cased, so
+// `SERVERS_0_HOST` is `servers[0].host`. The keys are matched in the relaxed form when
+// binding, `SERVER_MAXCONNS` sets both `server.maxConns` and `server.max-conns`.
+func envToKey(name string) string {
+	var sb strings.Builder
+	for _, s := range strings.Split(strings.ToLower(name), "_") {
+		if s == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(s); err == nil && sb.Len() > 0 {
+			sb.WriteString("[" + s + "]")
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(".")
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// matchEnvKey returns the key of the properties which the key of an environment variable
+// stands for, the `_` of the variable separates the keys or the words of a key, so the
+// key `http.read.timeout` of `GS_HTTP_READ_TIMEOUT` stands for `http.read-timeout`.
+func matchEnvKey(props *conf.Properties, key string) (string, bool) {
+	flat := flatKey(key)
+	for _, k := range props.Keys() {
+		if flatKey(k) == flat {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// flatKey returns the lower case key without separators.
+func flatKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '_', '[', ']':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, key)
+}
 
-	if !strings.HasPrefix(key, EnvPrefix) {
-		// append prefix
-		key = EnvPrefix + key
+// convertToEnv converts the property key to the environment variable name with prefix.
+func convertToEnv(prefix, key string) string {
+	r := strings.NewReplacer(".", "_", "[", "_", "]", "")
+	key = strings.ToUpper(r.Replace(key))
+	if !strings.HasPrefix(key, prefix) {
+		key = prefix + key
 	}
 	return key
 }
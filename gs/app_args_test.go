@@ -17,7 +17,12 @@
 package gs
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"testing"
+	"time"
 
 	"go-spring.dev/spring/conf"
 	"go-spring.dev/spring/internal/utils/assert"
@@ -41,6 +46,76 @@ func TestLoadCmdArgs(t *testing.T) {
 	})
 }
 
+func TestParseArguments(t *testing.T) {
+
+	_, err := ParseArguments([]string{"--=x"})
+	assert.Error(t, err, "invalid option \"--=x\"")
+
+	a, err := ParseArguments([]string{
+		"--profile=dev", "--port", "8080", "--verbose", "--no-cache",
+		"-test.v", "input.csv", "--tag", "a", "--tag=b", "--offset", "-1", "--", "--literal",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, a.OptionNames(), []string{"profile", "port", "verbose", "cache", "tag", "offset"})
+	assert.Equal(t, a.OptionValues("port"), []string{"8080"})
+	assert.Equal(t, a.OptionValues("verbose"), []string{"true"})
+	assert.Equal(t, a.OptionValues("cache"), []string{"false"})
+	assert.Equal(t, a.OptionValues("tag"), []string{"a", "b"})
+	assert.Equal(t, a.OptionValues("offset"), []string{"-1"})
+	assert.True(t, a.HasOption("profile"))
+	assert.False(t, a.HasOption("test.v"))
+	assert.Equal(t, a.Positional(), []string{"input.csv", "--literal"})
+
+	p := conf.New()
+	assert.Nil(t, a.bind(p))
+	assert.Equal(t, p.Get("port"), "8080")
+	assert.Equal(t, p.Get("cache"), "false")
+	var tags []string
+	assert.Nil(t, p.Bind(&tags, conf.Key("tag")))
+	assert.Equal(t, tags, []string{"a", "b"})
+}
+
+func TestApp_RunWithArgs(t *testing.T) {
+	os.Clearenv()
+	Setenv("GS_SPRING_APP_BATCH", "true")
+
+	type Service struct {
+		Args *ApplicationArguments `autowire:""`
+		Port int                   `value:"${port}"`
+	}
+	s := new(Service)
+	app := NewApp()
+	app.Object(s)
+	assert.Nil(t, app.RunWithArgs([]string{"--port", "9090", "job"}))
+	assert.Equal(t, s.Port, 9090)
+	assert.Equal(t, s.Args.Positional(), []string{"job"})
+}
+
+func TestApp_WithArgs(t *testing.T) {
+	os.Clearenv()
+	Setenv("GS_SPRING_APP_BATCH", "true")
+
+	args := os.Args
+	os.Args = []string{args[0], "--port", "9090"}
+	defer func() { os.Args = args }()
+
+	type Service struct {
+		Port int `value:"${port:=8080}"`
+	}
+	s := new(Service)
+	app := NewApp(WithArgs(nil))
+	app.Object(s)
+	assert.Nil(t, app.Run())
+	assert.Equal(t, s.Port, 8080)
+	assert.Nil(t, app.arguments.Args())
+
+	s = new(Service)
+	app = NewApp()
+	app.Object(s)
+	assert.Nil(t, app.Run())
+	assert.Equal(t, s.Port, 9090)
+}
+
 func TestConvertEnv(t *testing.T) {
 
 	var cases = []struct {
@@ -59,6 +134,115 @@ func TestConvertEnv(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		assert.Equal(t, convertToEnv(c.key), c.expect)
+		assert.Equal(t, convertToEnv(EnvPrefix, c.key), c.expect)
+	}
+}
+
+func TestEnvToKey(t *testing.T) {
+
+	var cases = []struct {
+		name   string
+		expect string
+	}{
+		{"A_B_C", "a.b.c"},
+		{"HTTP_READTIMEOUT", "http.readtimeout"},
+		{"SERVERS_0_HOST", "servers[0].host"},
+		{"MATRIX_0_1", "matrix[0][1]"},
+		{"0_A", "0.a"},
+		{"A__B", "a.b"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, envToKey(c.name), c.expect)
+	}
+
+	assert.Equal(t, convertToEnv("APP_", "servers[0].host"), "APP_SERVERS_0_HOST")
+}
+
+func TestLoadSystemEnv(t *testing.T) {
+	t.Setenv("APP_SERVERS_0_HOST", "a.com")
+	t.Setenv("APP_SERVERS_1_HOST", "b.com")
+	t.Setenv("APP_SERVER_MAXCONNS", "100")
+	t.Setenv("APP_HTTP_READTIMEOUT", "3s")
+	t.Setenv("APP_LOG", "on")
+	t.Setenv("APP_LOG_LEVEL", "debug")
+
+	p := conf.New()
+	loadSystemEnv(p, "APP_")
+	assert.Equal(t, p.Get("log"), "on")
+	assert.False(t, p.Has("log.level"))
+
+	var s struct {
+		Servers []struct {
+			Host string `value:"${host}"`
+		} `value:"${servers}"`
+		MaxConns    int           `value:"${server.maxConns}"`
+		ReadTimeout time.Duration `value:"${http.read-timeout}"`
 	}
+	err := p.Bind(&s)
+	assert.Nil(t, err)
+	assert.Equal(t, len(s.Servers), 2)
+	assert.Equal(t, s.Servers[1].Host, "b.com")
+	assert.Equal(t, s.MaxConns, 100)
+	assert.Equal(t, s.ReadTimeout, 3*time.Second)
+}
+
+func TestMatchEnvKey(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("http.read-timeout", "1s"))
+	assert.Nil(t, p.Set("servers[0].max-conns", "10"))
+	assert.Nil(t, p.Set("dbuser", "alice"))
+
+	key, ok := matchEnvKey(p, envToKey("HTTP_READ_TIMEOUT"))
+	assert.True(t, ok)
+	assert.Equal(t, key, "http.read-timeout")
+	key, ok = matchEnvKey(p, envToKey("SERVERS_0_MAX_CONNS"))
+	assert.True(t, ok)
+	assert.Equal(t, key, "servers[0].max-conns")
+	_, ok = matchEnvKey(p, envToKey("HTTP_WRITE_TIMEOUT"))
+	assert.False(t, ok)
+}
+
+func TestAppConfiguration_Env(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "application.yaml"), []byte("server:\n  max-conns: 10\n  zone: a\nhttp:\n  read-timeout: 1s\n"), 0600)
+	assert.Nil(t, err)
+	t.Setenv("APP_SPRING_CONFIG_LOCATIONS", dir)
+	t.Setenv("APP_SERVER_MAXCONNS", "100")
+	t.Setenv("APP_SERVER_ZONE_FIRST", "b")
+	t.Setenv("APP_HTTP_READ_TIMEOUT", "3s")
+
+	e := NewAppConfiguration(new(FileResourceLocator))
+	e.envPrefix = "APP_"
+	p := conf.New()
+	err = e.Load(p)
+	assert.Nil(t, err)
+	assert.Equal(t, p.Get("server.max-conns"), "100")
+	assert.False(t, p.Has("server.maxconns"))
+	assert.Equal(t, p.Origin("server.max-conns"), "env APP_SERVER_MAXCONNS")
+	assert.Equal(t, p.Get("server.zone"), "a")
+	assert.Equal(t, p.Get("http.read-timeout"), "3s")
+	assert.False(t, p.Has("http.read.timeout"))
+	assert.Equal(t, p.Origin("http.read-timeout"), "env APP_HTTP_READ_TIMEOUT")
+
+	e.args = []string{"--server.port", "80", "-D", "server.name=a"}
+	p = conf.New()
+	err = e.Load(p)
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("server.port"), "arg --server.port")
+	assert.Equal(t, p.Origin("server.name"), "arg -D server.name")
+
+	t.Setenv("APP_SERVER_MAXCONNS", "")
+	os.Unsetenv("APP_SERVER_MAXCONNS")
+	p = conf.New()
+	err = e.Load(p)
+	assert.Nil(t, err)
+	assert.Equal(t, p.Origin("server.max-conns"), filepath.Join(dir, "application.yaml")+":2")
+}
+
+func TestContainer_PropertyOrigin(t *testing.T) {
+	c := New().(*container)
+	c.Property("a", 1)
+	_, file, _, _ := runtime.Caller(0)
+	assert.Matches(t, c.props.Origin("a"), "^property "+regexp.QuoteMeta(file)+`:\d+$`)
 }
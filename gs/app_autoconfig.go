@@ -0,0 +1,212 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"go-spring.dev/spring/conf"
+)
+
+// AutoConfig is a named group of bean definitions provided by a starter. The beans of an
+// auto-configuration are registered after the user beans, so that their conditions such as
+// `OnMissingBean` can see the beans defined by the user and by the auto-configurations
+// ordered before it.
+type AutoConfig struct {
+	name   string
+	after  []string
+	before []string
+	fn     func(r BeanDefinitionRegistry) error
+	module *Module
+}
+
+var autoConfigs []*AutoConfig
+
+// AutoConfiguration register an auto-configuration, it can be disabled by adding its name
+// to the `spring.autoconfigure.exclude` property.
+//
+// example:
+//
+//	func init() {
+//		gs.AutoConfiguration("web", func(r gs.BeanDefinitionRegistry) error {
+//			r.Configuration(new(serverConfiguration)).On(cond.OnProperty("http.addr"))
+//			return nil
+//		}).After("metrics")
+//	}
+func AutoConfiguration(name string, fn func(r BeanDefinitionRegistry) error) *AutoConfig {
+	for _, ac := range autoConfigs {
+		if ac.name == name {
+			panic(fmt.Errorf("duplicate auto-configuration %q", name))
+		}
+	}
+	ac := &AutoConfig{name: name, fn: fn}
+	autoConfigs = append(autoConfigs, ac)
+	return ac
+}
+
+// Name Return the name of the auto-configuration.
+func (ac *AutoConfig) Name() string {
+	return ac.name
+}
+
+// After the auto-configuration is applied after the named ones.
+func (ac *AutoConfig) After(names ...string) *AutoConfig {
+	ac.after = append(ac.after, names...)
+	return ac
+}
+
+// Before the auto-configuration is applied before the named ones.
+func (ac *AutoConfig) Before(names ...string) *AutoConfig {
+	ac.before = append(ac.before, names...)
+	return ac
+}
+
+// AutoConfigModule returns the module of the named auto-configuration, so that an App created
+// with WithoutAutoConfigs can install the auto-configurations it needs explicitly, the modules
+// are installed in the order given instead of the After/Before relationships.
+func AutoConfigModule(name string) *Module {
+	for _, ac := range autoConfigs {
+		if ac.name == name {
+			if ac.module == nil {
+				ac.module = NewModule(ac.name, ac.fn)
+			}
+			return ac.module
+		}
+	}
+	panic(fmt.Errorf("auto-configuration %q not found", name))
+}
+
+// uninstalled returns the auto-configurations whose modules aren't installed explicitly,
+// which would register their beans twice otherwise.
+func uninstalled(configs []*AutoConfig, installed []*Module) []*AutoConfig {
+	var ret []*AutoConfig
+	for _, ac := range configs {
+		skip := false
+		for _, m := range installed {
+			if ac.module == m {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			ret = append(ret, ac)
+		}
+	}
+	return ret
+}
+
+// applyAutoConfigs register the beans of the enabled auto-configurations in their declared order.
+func applyAutoConfigs(r BeanDefinitionRegistry, configs []*AutoConfig) error {
+
+	var excludes []string
+	if err := r.Properties().Bind(&excludes, conf.Key("spring.autoconfigure.exclude:=")); err != nil {
+		return err
+	}
+
+	excluded := make(map[string]bool)
+	for _, name := range excludes {
+		excluded[name] = true
+	}
+
+	var enabled []*AutoConfig
+	for _, ac := range configs {
+		if excluded[ac.name] {
+			delete(excluded, ac.name)
+			continue
+		}
+		enabled = append(enabled, ac)
+	}
+
+	for name := range excluded {
+		GetLogger().Warn("excluded auto-configuration not found", slog.String("name", name))
+	}
+
+	sorted, err := sortAutoConfigs(enabled)
+	if err != nil {
+		return err
+	}
+
+	for _, ac := range sorted {
+		if err = ac.fn(r); err != nil {
+			return fmt.Errorf("auto-configuration %q error: %w", ac.name, err)
+		}
+	}
+	return nil
+}
+
+// sortAutoConfigs sorts the auto-configurations topologically by their After/Before relationships,
+// the ones without relationship are sorted by name. Unknown names in relationships are ignored.
+func sortAutoConfigs(configs []*AutoConfig) ([]*AutoConfig, error) {
+
+	byName := make(map[string]*AutoConfig)
+	for _, ac := range configs {
+		byName[ac.name] = ac
+	}
+
+	// edges from an auto-configuration to the ones that must be applied after it.
+	edges := make(map[string][]string)
+	inDegree := make(map[string]int)
+	for _, ac := range configs {
+		for _, name := range ac.after {
+			if _, ok := byName[name]; ok {
+				edges[name] = append(edges[name], ac.name)
+				inDegree[ac.name]++
+			}
+		}
+		for _, name := range ac.before {
+			if _, ok := byName[name]; ok {
+				edges[ac.name] = append(edges[ac.name], name)
+				inDegree[name]++
+			}
+		}
+	}
+
+	var ready []string
+	for _, ac := range configs {
+		if inDegree[ac.name] == 0 {
+			ready = append(ready, ac.name)
+		}
+	}
+
+	var sorted []*AutoConfig
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, byName[name])
+		for _, next := range edges[name] {
+			if inDegree[next]--; inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(sorted) < len(configs) {
+		var cycle []string
+		for _, ac := range configs {
+			if inDegree[ac.name] > 0 {
+				cycle = append(cycle, ac.name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("found cycle between auto-configurations [%s]", strings.Join(cycle, ", "))
+	}
+	return sorted, nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func newTestAutoConfig(name string, fn func(r BeanDefinitionRegistry) error) *AutoConfig {
+	return &AutoConfig{name: name, fn: fn}
+}
+
+func autoConfigNames(configs []*AutoConfig) []string {
+	var names []string
+	for _, ac := range configs {
+		names = append(names, ac.Name())
+	}
+	return names
+}
+
+func TestSortAutoConfigs(t *testing.T) {
+
+	t.Run("sorted by name", func(t *testing.T) {
+		sorted, err := sortAutoConfigs([]*AutoConfig{
+			newTestAutoConfig("c", nil),
+			newTestAutoConfig("a", nil),
+			newTestAutoConfig("b", nil),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, autoConfigNames(sorted), []string{"a", "b", "c"})
+	})
+
+	t.Run("after & before", func(t *testing.T) {
+		sorted, err := sortAutoConfigs([]*AutoConfig{
+			newTestAutoConfig("a", nil).After("web"),
+			newTestAutoConfig("web", nil).After("metrics", "unknown"),
+			newTestAutoConfig("z", nil).Before("metrics"),
+			newTestAutoConfig("metrics", nil),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, autoConfigNames(sorted), []string{"z", "metrics", "web", "a"})
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := sortAutoConfigs([]*AutoConfig{
+			newTestAutoConfig("a", nil).After("b"),
+			newTestAutoConfig("b", nil).After("a"),
+			newTestAutoConfig("c", nil),
+		})
+		assert.Error(t, err, "found cycle between auto-configurations \\[a, b\\]")
+	})
+}
+
+func TestApplyAutoConfigs(t *testing.T) {
+
+	configs := []*AutoConfig{
+		newTestAutoConfig("zero", func(r BeanDefinitionRegistry) error {
+			r.Object(&BeanZero{2}).On(cond.OnMissingBean((*BeanZero)(nil)))
+			return nil
+		}),
+		newTestAutoConfig("one", func(r BeanDefinitionRegistry) error {
+			r.Object(new(BeanOne)).On(cond.OnBean((*BeanZero)(nil)))
+			return nil
+		}).After("zero"),
+	}
+
+	t.Run("user bean first", func(t *testing.T) {
+		c := New()
+		c.Object(&BeanZero{1})
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			return applyAutoConfigs(r, configs)
+		}))
+		err := runTest(c, func(ctx Context) {
+			var one *BeanOne
+			err := ctx.Get(&one)
+			assert.Nil(t, err)
+			assert.Equal(t, one.Zero.Int, 1)
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("installed", func(t *testing.T) {
+		zero := configs[0]
+		zero.module = NewModule(zero.name, zero.fn)
+		defer func() { zero.module = nil }()
+		c := New().(*container)
+		c.Install(zero.module)
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			return applyAutoConfigs(r, uninstalled(configs, c.installed))
+		}))
+		err := runTest(c, func(ctx Context) {
+			var zeros []*BeanZero
+			err := ctx.Get(&zeros)
+			assert.Nil(t, err)
+			assert.Equal(t, len(zeros), 1)
+			var one *BeanOne
+			assert.Nil(t, ctx.Get(&one))
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		c := New()
+		p := conf.New()
+		p.Set("spring.autoconfigure.exclude", "zero")
+		err := c.Properties().Refresh(p)
+		assert.Nil(t, err)
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			return applyAutoConfigs(r, configs)
+		}))
+		err = runTest(c, func(ctx Context) {
+			var one *BeanOne
+			err := ctx.Get(&one)
+			assert.Error(t, err, "can't find bean")
+		})
+		assert.Nil(t, err)
+	})
+}
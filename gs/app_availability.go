@@ -0,0 +1,153 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"sync"
+)
+
+// LivenessState tells whether the application works, a broken application should be restarted.
+type LivenessState string
+
+const (
+	LivenessCorrect = LivenessState("CORRECT")
+	LivenessBroken  = LivenessState("BROKEN")
+)
+
+// ReadinessState tells whether the application can serve traffic.
+type ReadinessState string
+
+const (
+	ReadinessAccepting = ReadinessState("ACCEPTING_TRAFFIC")
+	ReadinessRefusing  = ReadinessState("REFUSING_TRAFFIC")
+)
+
+// AvailabilityChange is the availability of the application after a transition.
+type AvailabilityChange struct {
+	Liveness  LivenessState
+	Readiness ReadinessState
+}
+
+// AvailabilityListener is implemented by beans notified of availability transitions.
+type AvailabilityListener interface {
+	OnAvailabilityChange(change AvailabilityChange)
+}
+
+// Availability is the bean holding the liveness and readiness states of the application.
+// The application is live but refuses traffic until it has started, and refuses traffic
+// again as soon as it begins to shut down.
+type Availability struct {
+	mutex     sync.Mutex
+	liveness  LivenessState
+	readiness ReadinessState
+	listeners []AvailabilityListener
+}
+
+// NewAvailability returns an Availability which is live and refuses traffic.
+func NewAvailability() *Availability {
+	return &Availability{liveness: LivenessCorrect, readiness: ReadinessRefusing}
+}
+
+// Liveness returns the liveness state.
+func (a *Availability) Liveness() LivenessState {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.liveness
+}
+
+// Readiness returns the readiness state.
+func (a *Availability) Readiness() ReadinessState {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.readiness
+}
+
+// SetLiveness changes the liveness state and notifies the listeners when it changes.
+func (a *Availability) SetLiveness(state LivenessState) {
+	a.set(func() bool {
+		changed := a.liveness != state
+		a.liveness = state
+		return changed
+	})
+}
+
+// SetReadiness changes the readiness state and notifies the listeners when it changes.
+func (a *Availability) SetReadiness(state ReadinessState) {
+	a.set(func() bool {
+		changed := a.readiness != state
+		a.readiness = state
+		return changed
+	})
+}
+
+func (a *Availability) set(fn func() bool) {
+	a.mutex.Lock()
+	if !fn() {
+		a.mutex.Unlock()
+		return
+	}
+	change := AvailabilityChange{Liveness: a.liveness, Readiness: a.readiness}
+	listeners := append([]AvailabilityListener(nil), a.listeners...)
+	a.mutex.Unlock()
+
+	for _, l := range listeners {
+		l.OnAvailabilityChange(change)
+	}
+}
+
+// Listen registers a listener of availability transitions.
+func (a *Availability) Listen(l AvailabilityListener) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.listeners = append(a.listeners, l)
+}
+
+// AvailabilityListenerFunc is an adapter to use a function as an AvailabilityListener.
+type AvailabilityListenerFunc func(change AvailabilityChange)
+
+func (fn AvailabilityListenerFunc) OnAvailabilityChange(change AvailabilityChange) {
+	fn(change)
+}
+
+// livenessIndicator reports the liveness state as the health of the liveness group.
+type livenessIndicator struct {
+	a *Availability
+}
+
+func (i *livenessIndicator) Health(ctx context.Context) HealthStatus {
+	state := i.a.Liveness()
+	s := HealthStatus{State: HealthUp, Details: map[string]interface{}{"state": state}}
+	if state != LivenessCorrect {
+		s.State = HealthDown
+	}
+	return s
+}
+
+// readinessIndicator reports the readiness state as the health of the readiness group.
+type readinessIndicator struct {
+	a *Availability
+}
+
+func (i *readinessIndicator) Health(ctx context.Context) HealthStatus {
+	state := i.a.Readiness()
+	s := HealthStatus{State: HealthUp, Details: map[string]interface{}{"state": state}}
+	if state != ReadinessAccepting {
+		s.State = HealthDown
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestAvailability(t *testing.T) {
+	a := NewAvailability()
+	assert.Equal(t, a.Liveness(), LivenessCorrect)
+	assert.Equal(t, a.Readiness(), ReadinessRefusing)
+
+	var changes []AvailabilityChange
+	a.Listen(AvailabilityListenerFunc(func(change AvailabilityChange) {
+		changes = append(changes, change)
+	}))
+
+	a.SetReadiness(ReadinessAccepting)
+	a.SetReadiness(ReadinessAccepting) // not changed
+	a.SetLiveness(LivenessBroken)
+	assert.Equal(t, changes, []AvailabilityChange{
+		{Liveness: LivenessCorrect, Readiness: ReadinessAccepting},
+		{Liveness: LivenessBroken, Readiness: ReadinessAccepting},
+	})
+
+	s := (&livenessIndicator{a}).Health(context.Background())
+	assert.Equal(t, s.State, HealthDown)
+	s = (&readinessIndicator{a}).Health(context.Background())
+	assert.Equal(t, s, HealthStatus{State: HealthUp, Details: map[string]interface{}{"state": ReadinessAccepting}})
+}
+
+type availabilityRecorder struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (r *availabilityRecorder) record(event string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *availabilityRecorder) count() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.events)
+}
+
+func (r *availabilityRecorder) OnAvailabilityChange(change AvailabilityChange) {
+	r.record(string(change.Readiness))
+}
+
+func (r *availabilityRecorder) OnAppStart(ctx context.Context) { r.record("start") }
+func (r *availabilityRecorder) OnAppStop(ctx context.Context)  { r.record("stop") }
+
+func TestApp_Availability(t *testing.T) {
+	os.Clearenv()
+	Setenv("GS_SPRING_APP_DRAIN-DELAY", "50ms")
+
+	r := new(availabilityRecorder)
+	app := NewApp()
+	app.Object(r)
+
+	type PandoraAware struct{}
+	registryCh := make(chan *HealthRegistry, 1)
+	app.Provide(func(ctx Context) PandoraAware {
+		var registry *HealthRegistry
+		assert.Nil(t, ctx.Get(&registry))
+		registryCh <- registry
+		return PandoraAware{}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.Nil(t, app.Run())
+	}()
+	registry := <-registryCh
+	for r.count() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	report, ok := registry.Group(context.Background(), "readiness")
+	assert.True(t, ok)
+	assert.Equal(t, report.State, HealthUp)
+
+	start := time.Now()
+	app.Shutdown("test")
+	<-done
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.Equal(t, r.events, []string{"start", string(ReadinessAccepting), string(ReadinessRefusing), "stop"})
+}
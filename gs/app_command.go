@@ -0,0 +1,231 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs/cond"
+)
+
+// Command is implemented by beans which are the modes of the application, such as
+// `serve` or `migrate`. The first positional command line argument, or the property
+// `spring.command`, selects the active command, and only the beans of the active command
+// are registered, see cond.OnCommand. The flags of the command are bound into properties
+// of the same names, and the command runs after the application started, the application
+// exits once it returns, see Runner.
+//
+// Name, Description and Flags are called before the bean is created, so they mustn't
+// depend on the injected fields.
+type Command interface {
+	Name() string
+	Description() string
+	Flags(fs *flag.FlagSet)
+	Run(ctx context.Context, args []string) error
+}
+
+// activeCommand is the command selected by the command line arguments.
+type activeCommand struct {
+	name string
+	args []string
+}
+
+// commandPostProcessor selects the active command among the command beans, binds its
+// flags into properties and conditions the command beans on the active command.
+type commandPostProcessor struct {
+	app    *App
+	output io.Writer
+}
+
+func (p *commandPostProcessor) PostProcessBeanDefinitionRegistry(r BeanDefinitionRegistry) error {
+
+	commands := make(map[string]Command)
+	for _, b := range r.BeanDefinitions() {
+		c, ok := commandOf(b)
+		if !ok {
+			continue
+		}
+		if _, ok = commands[c.Name()]; ok {
+			return fmt.Errorf("duplicate command %q", c.Name())
+		}
+		commands[c.Name()] = c
+		b.On(cond.OnCommand(c.Name()))
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+	if err := p.reparseArguments(r, commands); err != nil {
+		return err
+	}
+
+	name, args := splitCommand(p.app.arguments)
+	if name == "" {
+		name = r.Properties().Get("spring.command")
+	}
+	if name == "" || name == "help" || isHelp(name) {
+		printCommands(p.output, commands)
+		if name == "" {
+			return errors.New("no command specified")
+		}
+		return flag.ErrHelp
+	}
+
+	c, ok := commands[name]
+	if !ok {
+		printCommands(p.output, commands)
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(p.output)
+	fs.Usage = func() {
+		fmt.Fprintf(p.output, "Usage of %s: %s\n", name, c.Description())
+		fs.PrintDefaults()
+	}
+	c.Flags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		// the defaults of flags don't override the configured properties.
+		if isSetFlag(fs, f.Name) {
+			err = r.Properties().Set(f.Name, f.Value.String(), conf.From("arg --"+f.Name))
+		} else if !r.Properties().Has(f.Name) {
+			err = r.Properties().Set(f.Name, f.Value.String(), conf.From("command "+name+" default"))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.Properties().Set("spring.command", name); err != nil {
+		return err
+	}
+	p.app.command = &activeCommand{name: name, args: fs.Args()}
+	return nil
+}
+
+// reparseArguments parses the arguments again with the commands, and rebinds the options
+// which took a command as their values, such as verbose of `--verbose migrate`.
+func (p *commandPostProcessor) reparseArguments(r BeanDefinitionRegistry, commands map[string]Command) error {
+	a, err := parseArguments(p.app.arguments.args, commands)
+	if err != nil {
+		return err
+	}
+	for _, name := range a.names {
+		if reflect.DeepEqual(a.options[name], p.app.arguments.options[name]) {
+			continue
+		}
+		err = a.bindOption(name, func(key, value string, opts ...conf.SetOption) error {
+			// the options aren't bound when the properties are given by WithProperties.
+			if r.Properties().Origin(key) != "arg --"+name {
+				return nil
+			}
+			return r.Properties().Set(key, value, opts...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	*p.app.arguments = *a
+	return nil
+}
+
+// commandOf returns the command of the bean, the bean is created with zero value when
+// it is not created yet.
+func commandOf(b *BeanDefinition) (Command, bool) {
+	t := reflect.TypeOf((*Command)(nil)).Elem()
+	if !b.Type().Implements(t) {
+		return nil, false
+	}
+	v := b.Value()
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		v = reflect.New(v.Type().Elem())
+	}
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return nil, false
+	}
+	return v.Interface().(Command), true
+}
+
+// splitCommand returns the first positional argument and the arguments after it, or the
+// help option before it.
+func splitCommand(a *ApplicationArguments) (string, []string) {
+	end := len(a.args)
+	if len(a.positional) > 0 {
+		end = a.positional[0]
+	}
+	for _, s := range a.args[:end] {
+		if isHelp(s) {
+			return s, nil
+		}
+	}
+	if len(a.positional) == 0 {
+		return "", nil
+	}
+	return a.args[end], a.args[end+1:]
+}
+
+func isHelp(s string) bool {
+	return s == "-h" || s == "-help" || s == "--help"
+}
+
+func isSetFlag(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+func printCommands(w io.Writer, commands map[string]Command) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-16s %s\n", name, commands[name].Description())
+	}
+}
+
+// runCommand runs the active command, which is the only command bean registered.
+func (app *App) runCommand(ctx context.Context) error {
+	for _, bean := range app.container.Dependencies(true) {
+		if c, ok := bean.Interface().(Command); ok && c.Name() == app.command.name {
+			if err := c.Run(ctx, app.command.args); err != nil {
+				return fmt.Errorf("command %q error: %w", c.Name(), err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("command %q not found", app.command.name)
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+type migrateCommand struct {
+	DryRun bool `value:"${dry-run}"`
+	Steps  int  `value:"${steps}"`
+	args   []string
+}
+
+func (c *migrateCommand) Name() string        { return "migrate" }
+func (c *migrateCommand) Description() string { return "Migrate the database." }
+
+func (c *migrateCommand) Flags(fs *flag.FlagSet) {
+	fs.Bool("dry-run", false, "print the statements only")
+	fs.Int("steps", 1, "the number of steps")
+}
+
+func (c *migrateCommand) Run(ctx context.Context, args []string) error {
+	c.args = args
+	return nil
+}
+
+type serveCommand struct{}
+
+func (c *serveCommand) Name() string                                 { return "serve" }
+func (c *serveCommand) Description() string                          { return "Serve the http requests." }
+func (c *serveCommand) Flags(fs *flag.FlagSet)                       {}
+func (c *serveCommand) Run(ctx context.Context, args []string) error { <-ctx.Done(); return nil }
+
+func TestSplitCommand(t *testing.T) {
+	for _, c := range []struct {
+		args    []string
+		command string
+		rest    []string
+	}{
+		{[]string{"-D", "a=b", "--profile", "dev", "migrate", "--steps", "2", "x"}, "migrate", []string{"--steps", "2", "x"}},
+		{[]string{"-D", "a=b"}, "", nil},
+		{[]string{"--help", "migrate"}, "--help", nil},
+		{[]string{"migrate", "--help"}, "migrate", []string{"--help"}},
+	} {
+		a, err := ParseArguments(c.args)
+		assert.Nil(t, err)
+		command, rest := splitCommand(a)
+		assert.Equal(t, command, c.command)
+		assert.Equal(t, rest, c.rest)
+	}
+}
+
+func TestPrintCommands(t *testing.T) {
+	var buf bytes.Buffer
+	printCommands(&buf, map[string]Command{
+		"serve":   new(serveCommand),
+		"migrate": new(migrateCommand),
+	})
+	assert.Equal(t, buf.String(), "Commands:\n"+
+		"  migrate          Migrate the database.\n"+
+		"  serve            Serve the http requests.\n")
+}
+
+func TestApp_Command(t *testing.T) {
+
+	t.Run("migrate", func(t *testing.T) {
+		os.Clearenv()
+
+		type PandoraAware struct{}
+		var served bool
+		migrate := new(migrateCommand)
+		app := NewApp()
+		app.Object(migrate)
+		app.Object(new(serveCommand))
+		app.Provide(func() PandoraAware {
+			served = true
+			return PandoraAware{}
+		}).On(cond.OnCommand("serve"))
+		assert.Nil(t, app.RunWithArgs([]string{"migrate", "--dry-run", "v2"}))
+		assert.True(t, migrate.DryRun)
+		assert.Equal(t, migrate.Steps, 1)
+		assert.Equal(t, migrate.args, []string{"v2"})
+		assert.False(t, served)
+	})
+
+	t.Run("option before command", func(t *testing.T) {
+		os.Clearenv()
+
+		type Options struct {
+			Verbose bool `value:"${verbose}"`
+			Offset  int  `value:"${offset}"`
+		}
+		migrate := new(migrateCommand)
+		options := new(Options)
+		app := NewApp()
+		app.Object(migrate)
+		app.Object(options)
+		assert.Nil(t, app.RunWithArgs([]string{"--offset", "-1", "--verbose", "migrate", "--steps", "2", "v2"}))
+		assert.True(t, options.Verbose)
+		assert.Equal(t, options.Offset, -1)
+		assert.Equal(t, migrate.Steps, 2)
+		assert.Equal(t, migrate.args, []string{"v2"})
+		assert.Equal(t, app.arguments.Positional(), []string{"migrate", "v2"})
+	})
+
+	t.Run("property", func(t *testing.T) {
+		os.Clearenv()
+		Setenv("GS_SPRING_COMMAND", "migrate")
+		Setenv("GS_STEPS", "3")
+
+		migrate := new(migrateCommand)
+		app := NewApp()
+		app.Object(migrate)
+		assert.Nil(t, app.RunWithArgs(nil))
+		assert.Equal(t, migrate.Steps, 3)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		os.Clearenv()
+		app := NewApp()
+		app.Object(new(migrateCommand))
+		assert.Error(t, app.RunWithArgs([]string{"reindex"}), "unknown command \"reindex\"")
+	})
+}
@@ -18,7 +18,7 @@ package gs
 
 import (
 	"io/ioutil"
-	"os"
+	"log/slog"
 	"path/filepath"
 
 	"go-spring.dev/spring/conf"
@@ -26,21 +26,22 @@ import (
 
 type AppConfiguration struct {
 	resourceLocator  ResourceLocator
+	args             []string
+	envPrefix        string
 	ActiveProfiles   []string `value:"${spring.config.profiles:=}"`
 	ConfigExtensions []string `value:"${spring.config.extensions:=.properties,.yaml,.yml,.toml,.tml}"`
 }
 
 func NewAppConfiguration(resourceLocator ResourceLocator) *AppConfiguration {
-	return &AppConfiguration{resourceLocator: resourceLocator}
+	return &AppConfiguration{resourceLocator: resourceLocator, envPrefix: EnvPrefix}
 }
 
 func (e *AppConfiguration) Load(props *conf.Properties) error {
-	p := conf.New()
+	env := conf.New()
+	loadSystemEnv(env, e.envPrefix)
 
-	if err := loadSystemEnv(p); err != nil {
-		return err
-	}
-	if err := loadCmdArgs(os.Args, p); err != nil {
+	p := env.Copy()
+	if err := loadCmdArgs(e.args, p); err != nil {
 		return err
 	}
 	if err := p.Bind(e); err != nil {
@@ -56,8 +57,22 @@ func (e *AppConfiguration) Load(props *conf.Properties) error {
 	}
 
 	// 从环境变量和参数获取的配置优先级更高
+	// 按宽松形式覆盖文件中的配置，如 `server.maxconns` 覆盖 `server.max-conns`，
+	// 环境变量还按文件中已有的配置消除歧义，如 `http.read.timeout` 覆盖 `http.read-timeout`，
+	// 与文件中的配置冲突的属性被忽略，如文件中的 `log` 和环境变量的 `log.level`
 	for _, k := range p.Keys() {
-		props.Set(k, p.Get(k))
+		key := k
+		if s, ok := props.Lookup(k); ok {
+			key = s
+		} else if env.Has(k) && env.Origin(k) == p.Origin(k) {
+			if s, ok = matchEnvKey(props, k); ok {
+				key = s
+			}
+		}
+		if err := props.Set(key, p.Get(k), conf.From(p.Origin(k))); err != nil {
+			GetLogger().Warn("skip conflicting property", slog.String("key", k),
+				slog.String("origin", p.Origin(k)), slog.Any("err", err))
+		}
 	}
 	return nil
 }
@@ -94,12 +109,12 @@ func (e *AppConfiguration) loadProperties(props *conf.Properties) error {
 		if err != nil {
 			return err
 		}
-		p, err := conf.Bytes(b, filepath.Ext(resource.Name()))
-		if err != nil {
+		p := conf.New()
+		if err = p.Bytes(b, filepath.Ext(resource.Name()), conf.From(resource.Name())); err != nil {
 			return err
 		}
 		for _, key := range p.Keys() {
-			props.Set(key, p.Get(key))
+			props.Set(key, p.Get(key), conf.From(p.Origin(key)))
 		}
 	}
 
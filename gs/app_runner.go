@@ -0,0 +1,107 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Runner is implemented by beans run after the application started, the runners are run
+// one by one in the ascending order of their bean Order with the positional command line
+// arguments. The application is shut down when a runner fails, and App.Run returns the
+// error.
+//
+// When the property `spring.app.batch` is true, the application exits once all runners
+// finished, which suits command line tools and batch jobs.
+type Runner interface {
+	Run(ctx context.Context, args []string) error
+}
+
+// ExitCoder is implemented by errors carrying the exit code of the process.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitError is an error with the exit code of the process.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit code %d: %v", e.Code, e.Err)
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+func (e *ExitError) ExitCode() int { return e.Code }
+
+// ExitCode returns the exit code of the process for the error returned by App.Run, that is
+// 0 for nil, the code of the first ExitCoder in the error chain, or 1.
+//
+//	os.Exit(gs.ExitCode(gs.Run()))
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ec ExitCoder
+	if errors.As(err, &ec) {
+		return ec.ExitCode()
+	}
+	return 1
+}
+
+// runners returns the runner beans sorted by their bean Order, then by their dependencies,
+// the commands are not runners even though they have the same Run method.
+func (app *App) runners() []Runner {
+	var beans []*BeanDefinition
+	for _, bean := range app.container.Dependencies(true) {
+		if _, ok := bean.Interface().(Command); ok {
+			continue
+		}
+		if _, ok := bean.Interface().(Runner); ok {
+			beans = append(beans, bean)
+		}
+	}
+	sort.SliceStable(beans, func(i, j int) bool {
+		return beans[i].GetOrder() < beans[j].GetOrder()
+	})
+	runners := make([]Runner, 0, len(beans))
+	for _, bean := range beans {
+		runners = append(runners, bean.Interface().(Runner))
+	}
+	return runners
+}
+
+// runRunners runs the runners until one fails or the context is canceled.
+func runRunners(ctx context.Context, runners []Runner, args []string) error {
+	logger := GetLogger()
+	for _, r := range runners {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		logger.Info("running runner", slog.String("runner", fmt.Sprintf("%T", r)))
+		if err := r.Run(ctx, args); err != nil {
+			return fmt.Errorf("runner %T error: %w", r, err)
+		}
+	}
+	return nil
+}
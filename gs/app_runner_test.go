@@ -0,0 +1,84 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+type recordRunner struct {
+	name    string
+	err     error
+	mutex   *sync.Mutex
+	records *[]string
+}
+
+func (r *recordRunner) Run(ctx context.Context, args []string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	*r.records = append(*r.records, r.name)
+	return r.err
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCode(nil), 0)
+	assert.Equal(t, ExitCode(errors.New("error")), 1)
+	err := &ExitError{Code: 3, Err: errors.New("no input")}
+	assert.Equal(t, err.Error(), "exit code 3: no input")
+	assert.Equal(t, ExitCode(err), 3)
+	assert.Equal(t, ExitCode(errors.Join(errors.New("wrapped"), err)), 3)
+}
+
+func TestApp_Runners(t *testing.T) {
+
+	t.Run("batch", func(t *testing.T) {
+		os.Clearenv()
+		Setenv("GS_SPRING_APP_BATCH", "true")
+
+		var (
+			mutex   sync.Mutex
+			records []string
+		)
+		app := NewApp()
+		app.Object(&recordRunner{name: "b", mutex: &mutex, records: &records}).Name("b").Order(2)
+		app.Object(&recordRunner{name: "a", mutex: &mutex, records: &records}).Name("a").Order(1)
+		assert.Nil(t, app.Run())
+		assert.Equal(t, records, []string{"a", "b"})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		os.Clearenv()
+
+		var (
+			mutex   sync.Mutex
+			records []string
+		)
+		app := NewApp()
+		app.Object(&recordRunner{name: "a", mutex: &mutex, records: &records, err: &ExitError{Code: 2, Err: errors.New("bad input")}}).Name("a").Order(1)
+		app.Object(&recordRunner{name: "b", mutex: &mutex, records: &records}).Name("b").Order(2)
+		err := app.Run()
+		assert.Error(t, err, "runner \\*gs.recordRunner error: exit code 2: bad input")
+		assert.Equal(t, ExitCode(err), 2)
+		assert.Equal(t, records, []string{"a"})
+	})
+}
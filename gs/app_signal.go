@@ -0,0 +1,216 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/dync"
+)
+
+// SignalHandler is implemented by beans handling the signals of the process, the handlers
+// are invoked one by one in the order of their bean definitions after the built-in ones,
+// so they should return quickly.
+//
+// The built-in handlers are:
+//
+//	SIGINT, SIGTERM  shut down the application, a second SIGINT exits immediately.
+//	SIGHUP           reload the properties, see App.Reload.
+//	SIGUSR1          log the stacks of the goroutines and the beans.
+type SignalHandler interface {
+	Signals() []os.Signal
+	HandleSignal(ctx context.Context, sig os.Signal)
+}
+
+// SignalHandlerFunc is a SignalHandler of the signals.
+func SignalHandlerFunc(fn func(ctx context.Context, sig os.Signal), signals ...os.Signal) SignalHandler {
+	return &signalHandlerFunc{signals: signals, fn: fn}
+}
+
+type signalHandlerFunc struct {
+	signals []os.Signal
+	fn      func(ctx context.Context, sig os.Signal)
+}
+
+func (h *signalHandlerFunc) Signals() []os.Signal { return h.signals }
+
+func (h *signalHandlerFunc) HandleSignal(ctx context.Context, sig os.Signal) { h.fn(ctx, sig) }
+
+// osExit exits the process when the application is forced to exit.
+var osExit = os.Exit
+
+// signalHandlers returns the built-in handlers and the handler beans.
+func (app *App) signalHandlers() []SignalHandler {
+	handlers := []SignalHandler{
+		SignalHandlerFunc(app.onShutdownSignal, os.Interrupt, syscall.SIGTERM),
+	}
+	if len(reloadSignals) > 0 {
+		handlers = append(handlers, SignalHandlerFunc(func(ctx context.Context, sig os.Signal) {
+			if err := app.Reload(); err != nil {
+				GetLogger().Error("reload properties failed", slog.Any("err", err))
+			}
+		}, reloadSignals...))
+	}
+	if len(dumpSignals) > 0 {
+		handlers = append(handlers, SignalHandlerFunc(func(ctx context.Context, sig os.Signal) {
+			app.dump()
+		}, dumpSignals...))
+	}
+	for _, bean := range app.container.Dependencies(true) {
+		if h, ok := bean.Interface().(SignalHandler); ok {
+			handlers = append(handlers, h)
+		}
+	}
+	return handlers
+}
+
+// handleSignals dispatches the signals to the handlers until the application stopped.
+func (app *App) handleSignals() {
+	handlers := make(map[os.Signal][]SignalHandler)
+	var signals []os.Signal
+	for _, h := range app.signalHandlers() {
+		for _, sig := range h.Signals() {
+			if _, ok := handlers[sig]; !ok {
+				signals = append(signals, sig)
+			}
+			handlers[sig] = append(handlers[sig], h)
+		}
+	}
+
+	app.signals = make(chan os.Signal, 1)
+	app.signalDone = make(chan struct{})
+	signal.Notify(app.signals, signals...)
+
+	ctx := WithContext(app.container.Context(), app.container)
+	go func() {
+		for {
+			select {
+			case sig := <-app.signals:
+				GetLogger().Info("signal received", slog.String("signal", sig.String()))
+				for _, h := range handlers[sig] {
+					h.HandleSignal(ctx, sig)
+				}
+			case <-app.signalDone:
+				return
+			}
+		}
+	}()
+}
+
+// stopSignals stops dispatching the signals.
+func (app *App) stopSignals() {
+	if app.signals != nil {
+		signal.Stop(app.signals)
+		close(app.signalDone)
+	}
+}
+
+// onShutdownSignal shuts down the application, or exits immediately on a second SIGINT.
+func (app *App) onShutdownSignal(ctx context.Context, sig os.Signal) {
+	select {
+	case <-app.exitChan:
+		if sig == os.Interrupt {
+			GetLogger().Warn("forced to exit")
+			osExit(130)
+		}
+	default:
+		app.Shutdown(fmt.Sprintf("signal %v", sig))
+	}
+}
+
+// Reload loads the properties again and refreshes the dynamic properties. The properties
+// set by Property and those set while refreshing, such as the defaults of the modules and
+// the flags of the command, are applied again, the other properties removed are dropped.
+// It returns an error before the app started.
+func (app *App) Reload() error {
+	if app.defaults == nil {
+		return errors.New("app not started")
+	}
+	props := app.base.Copy()
+	if app.props != nil {
+		for _, key := range app.props.Keys() {
+			if err := props.Set(key, app.props.Get(key), conf.From(app.props.Origin(key))); err != nil {
+				return err
+			}
+		}
+	} else {
+		e := NewAppConfiguration(app.locator)
+		e.args = app.args
+		e.envPrefix = app.envPrefix
+		if err := e.Load(props); err != nil {
+			return err
+		}
+	}
+	for _, key := range app.defaults.Keys() {
+		if !props.Has(key) {
+			_ = props.Set(key, app.defaults.Get(key), conf.From(app.defaults.Origin(key)))
+		}
+	}
+	for _, key := range app.overrides.Keys() {
+		_ = props.Set(key, app.overrides.Get(key), conf.From(app.overrides.Origin(key)))
+	}
+	if err := app.container.p.Refresh(props); err != nil {
+		return err
+	}
+	GetLogger().Info("properties reloaded")
+	return nil
+}
+
+// refreshedProperties returns the properties added and those changed while refreshing the
+// container, comparing the properties loaded with the refreshed ones.
+func refreshedProperties(loaded *conf.Properties, refreshed *dync.Properties) (defaults, overrides *conf.Properties) {
+	defaults, overrides = conf.New(), conf.New()
+	for _, key := range refreshed.Keys() {
+		val, origin := refreshed.Get(key), conf.From(refreshed.Origin(key))
+		if !loaded.Has(key) {
+			_ = defaults.Set(key, val, origin)
+		} else if loaded.Get(key) != val {
+			_ = overrides.Set(key, val, origin)
+		}
+	}
+	return
+}
+
+// dump logs the stacks of the goroutines and the beans.
+func (app *App) dump() {
+	logger := GetLogger()
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	logger.Info("goroutine stacks", slog.String("stacks", string(buf)))
+	for _, bean := range app.container.Dependencies(true) {
+		logger.Info("bean", slog.String("bean", bean.String()))
+	}
+	// the values aren't logged, they may be secrets.
+	for _, key := range app.container.p.Keys() {
+		logger.Info("property", slog.String("key", key), slog.String("origin", app.container.p.Origin(key)))
+	}
+}
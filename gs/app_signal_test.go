@@ -0,0 +1,84 @@
+//go:build unix
+
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestApp_Signals(t *testing.T) {
+	os.Clearenv()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "application.properties")
+	assert.Nil(t, os.WriteFile(file, []byte("app.name=first\napp.removed=true\n"), 0644))
+	Setenv("GS_SPRING_CONFIG_LOCATIONS", dir)
+	Setenv("GS_SPRING_CONFIG_BANNER", "false")
+
+	received := make(chan os.Signal, 1)
+	app := NewApp(WithArgs(nil))
+	assert.Error(t, app.Reload(), "app not started")
+	app.Property("app.code", "gs")
+	app.Object(SignalHandlerFunc(func(ctx context.Context, sig os.Signal) {
+		assert.NotNil(t, FromContext(ctx))
+		received <- sig
+	}, syscall.SIGUSR2)).Export((*SignalHandler)(nil))
+
+	assert.Nil(t, app.Start(context.Background()))
+	assert.Equal(t, app.container.p.Get("app.name"), "first")
+	assert.True(t, app.container.p.Has("app.removed"))
+
+	assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	select {
+	case sig := <-received:
+		assert.Equal(t, sig, syscall.SIGUSR2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal not received")
+	}
+
+	assert.Nil(t, os.WriteFile(file, []byte("app.name=second\n"), 0644))
+	assert.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	for deadline := time.Now().Add(5 * time.Second); app.container.p.Get("app.name") != "second"; {
+		if time.Now().After(deadline) {
+			t.Fatal("properties not reloaded")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Nil(t, app.Reload())
+	assert.Equal(t, app.container.p.Get("spring.config.banner"), "false")
+	assert.Equal(t, app.container.p.Get("app.code"), "gs")
+	assert.False(t, app.container.p.Has("app.removed"))
+
+	app.dump()
+
+	var code int
+	osExit = func(c int) { code = c }
+	defer func() { osExit = os.Exit }()
+	app.onShutdownSignal(context.Background(), syscall.SIGTERM)
+	app.onShutdownSignal(context.Background(), os.Interrupt)
+	assert.Equal(t, code, 130)
+	assert.Nil(t, app.Stop(context.Background()))
+}
@@ -17,10 +17,15 @@
 package gs
 
 import (
+	"context"
+	"errors"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/conf/crypt"
 	"go-spring.dev/spring/internal/utils/assert"
 )
 
@@ -80,3 +85,79 @@ func TestConfig(t *testing.T) {
 		defer app.Shutdown("run test end")
 	})
 }
+
+func TestApp_Shutdown(t *testing.T) {
+	app := NewApp()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.Shutdown("test")
+		}()
+	}
+	wg.Wait()
+	<-app.exitChan
+	assert.Equal(t, app.availability.Readiness(), ReadinessRefusing)
+}
+
+type lifecycleRecorder struct {
+	Name   string `value:"${app.name}"`
+	events []string
+}
+
+func (r *lifecycleRecorder) OnAppStart(ctx context.Context) { r.events = append(r.events, "start") }
+func (r *lifecycleRecorder) OnAppStop(ctx context.Context)  { r.events = append(r.events, "stop") }
+
+func TestApp_StartStop(t *testing.T) {
+	os.Clearenv()
+	Setenv("GS_APP_NAME", "env")
+
+	p := conf.New()
+	assert.Nil(t, p.Set("app.name", "embedded"))
+	assert.Nil(t, p.Set("spring.config.banner", "false"))
+
+	r := new(lifecycleRecorder)
+	app := NewApp(WithProperties(p), WithoutSignals(), WithArgs(nil))
+	app.Object(r)
+
+	assert.Error(t, app.Stop(context.Background()), "app not started")
+	assert.Nil(t, app.Start(context.Background()))
+	assert.Error(t, app.Start(context.Background()), "app already started")
+	assert.Equal(t, r.Name, "embedded")
+	assert.Equal(t, r.events, []string{"start"})
+
+	assert.Nil(t, app.Stop(context.Background()))
+	assert.Nil(t, app.Stop(context.Background()))
+	assert.Equal(t, r.events, []string{"start", "stop"})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		app := NewApp(WithProperties(conf.New()), WithoutSignals())
+		err := app.Start(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
+func TestApp_EncryptedProperties(t *testing.T) {
+	os.Clearenv()
+	defer conf.SetDecryptor(nil)
+
+	key, err := crypt.GenerateKey()
+	assert.Nil(t, err)
+	t.Setenv(crypt.KeyEnv, key)
+	c, err := crypt.FromEnv()
+	assert.Nil(t, err)
+	ciphertext, err := c.Encrypt("secret")
+	assert.Nil(t, err)
+
+	p := conf.New()
+	assert.Nil(t, p.Set("app.name", "{cipher}"+ciphertext))
+	r := new(lifecycleRecorder)
+	app := NewApp(WithProperties(p), WithoutSignals(), WithArgs(nil))
+	app.Object(r)
+	assert.Nil(t, app.Start(context.Background()))
+	assert.Nil(t, app.Stop(context.Background()))
+	assert.Equal(t, r.Name, "secret")
+}
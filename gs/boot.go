@@ -27,9 +27,9 @@ import (
 
 var bootApp = NewApp()
 
-// Setenv convert property syntax to env.
+// Setenv convert property syntax to env, with the prefix of the boot app.
 func Setenv(key string, value string) {
-	key = convertToEnv(key)
+	key = convertToEnv(bootApp.envPrefix, key)
 	err := os.Setenv(key, value)
 	utils.Panic(err).When(err != nil)
 }
@@ -39,6 +39,11 @@ func Run(resourceLocator ...ResourceLocator) error {
 	return bootApp.Run(resourceLocator...)
 }
 
+// RunWithArgs start boot app with the command line arguments, without the program name.
+func RunWithArgs(args []string, resourceLocator ...ResourceLocator) error {
+	return bootApp.RunWithArgs(args, resourceLocator...)
+}
+
 // Shutdown close boot app.
 func Shutdown(msg ...string) {
 	bootApp.Shutdown(msg...)
@@ -51,7 +56,7 @@ func OnProperty(key string, fn interface{}) {
 
 // Property set property key/value.
 func Property(key string, fn interface{}) {
-	bootApp.Property(key, fn)
+	bootApp.container.property(key, fn, 2)
 }
 
 // Accept register bean to Ioc container.
@@ -74,9 +79,19 @@ func Configuration(i interface{}) *BeanDefinition {
 	return bootApp.Configuration(NewBean(reflect.ValueOf(i)).Caller(2))
 }
 
+// Install install the modules to boot app.
+func Install(modules ...*Module) {
+	bootApp.Install(modules...)
+}
+
+// PostProcessor register a BeanDefinitionRegistryPostProcessor to Ioc container.
+func PostProcessor(p BeanDefinitionRegistryPostProcessor) {
+	bootApp.PostProcessor(p)
+}
+
 // Go start a goroutine managed by the IoC container.
-func Go(fn func(ctx context.Context)) {
-	bootApp.Go(fn)
+func Go(fn func(ctx context.Context), opts ...GoOption) {
+	bootApp.Go(fn, opts...)
 }
 
 // AllowCircularReferences enable circular-references.
@@ -433,3 +433,15 @@ func OnProfile(profile string) *conditional {
 func (c *conditional) OnProfile(profile string) *conditional {
 	return c.OnProperty("spring.config.profiles", HavingValue(profile))
 }
+
+// OnCommand returns a conditional that starts with a Condition that returns true
+// when the command is the active command of the application.
+func OnCommand(command string) *conditional {
+	return New().OnCommand(command)
+}
+
+// OnCommand adds a Condition that returns true when the command is the active command
+// of the application, which is the property `spring.command`.
+func (c *conditional) OnCommand(command string) *conditional {
+	return c.OnProperty("spring.command", HavingValue(command))
+}
@@ -398,6 +398,38 @@ func TestOnProfile(t *testing.T) {
 	})
 }
 
+func TestOnCommand(t *testing.T) {
+	t.Run("no property", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ctx := NewMockContext(ctrl)
+		ctx.EXPECT().Has("spring.command").Return(false)
+		ok, err := OnCommand("migrate").Matches(ctx)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+	t.Run("diff property", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ctx := NewMockContext(ctrl)
+		ctx.EXPECT().Has("spring.command").Return(true)
+		ctx.EXPECT().Prop("spring.command").Return("serve")
+		ok, err := OnCommand("migrate").Matches(ctx)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+	t.Run("same property", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ctx := NewMockContext(ctrl)
+		ctx.EXPECT().Has("spring.command").Return(true)
+		ctx.EXPECT().Prop("spring.command").Return("migrate")
+		ok, err := OnCommand("migrate").Matches(ctx)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	})
+}
+
 func TestConditional(t *testing.T) {
 	t.Run("ok && ", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -25,7 +25,6 @@ import (
 	"log/slog"
 	"reflect"
 	"runtime"
-	"runtime/debug"
 	"sort"
 	"strings"
 	"sync"
@@ -61,6 +60,8 @@ type Container interface {
 	Object(i interface{}) *BeanDefinition
 	Provide(ctor interface{}, args ...arg.Arg) *BeanDefinition
 	Configuration(i interface{}) *BeanDefinition
+	PostProcessor(p BeanDefinitionRegistryPostProcessor)
+	Install(modules ...*Module)
 	Refresh() error
 	Close()
 }
@@ -71,12 +72,15 @@ type Context interface {
 	Keys() []string
 	Has(key string) bool
 	Prop(key string, opts ...conf.GetOption) string
+	Origin(key string) string
 	Resolve(s string) (string, error)
 	Bind(i interface{}, args ...conf.BindArg) error
 	Get(i interface{}, selectors ...BeanSelector) error
+	Beans() []*BeanDefinition
 	Wire(objOrCtor interface{}, ctorArgs ...arg.Arg) (interface{}, error)
 	Invoke(fn interface{}, args ...arg.Arg) ([]interface{}, error)
-	Go(fn func(ctx context.Context))
+	Go(fn func(ctx context.Context), opts ...GoOption)
+	Goroutines() []GoroutineInfo
 }
 
 type contextKey struct{}
@@ -98,6 +102,10 @@ type tempContainer struct {
 	beansByName     map[string][]*BeanDefinition
 	beansByType     map[reflect.Type][]*BeanDefinition
 	mapOfOnProperty map[string]interface{}
+	postProcessors  []BeanDefinitionRegistryPostProcessor
+	modules         []*Module
+	installed       []*Module
+	resolving       []*BeanDefinition
 }
 
 // The container is the cornerstone of the go-spring framework, implementing the concept of dependency injection mentioned in
@@ -118,6 +126,9 @@ type container struct {
 	dependencies            []*BeanDefinition
 	state                   refreshState
 	wg                      sync.WaitGroup
+	goMutex                 sync.Mutex
+	goSeq                   uint64
+	goroutines              map[uint64]*goroutine
 	p                       *dync.Properties
 	contextAware            bool
 	allowCircularReferences bool
@@ -127,9 +138,10 @@ type container struct {
 func New() Container {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &container{
-		ctx:    ctx,
-		cancel: cancel,
-		p:      dync.New(),
+		ctx:        ctx,
+		cancel:     cancel,
+		p:          dync.New(),
+		goroutines: make(map[uint64]*goroutine),
 		tempContainer: &tempContainer{
 			props:           conf.New(),
 			beansByName:     make(map[string][]*BeanDefinition),
@@ -170,7 +182,17 @@ func (c *container) OnProperty(key string, fn interface{}) {
 // Property sets the value of the property corresponding to the key. If the property value for the key already exists, the Set method will overwrite the old value.
 // In addition to supporting string type property values, the Set method also supports other primitive data types such as int, uint, bool, and so on for property values.
 func (c *container) Property(key string, value interface{}) {
-	c.props.Set(key, value)
+	c.property(key, value, 2)
+}
+
+// property sets the property with the origin of the caller, skip is the number of the
+// stack frames above property.
+func (c *container) property(key string, value interface{}, skip int) {
+	origin := "property"
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		origin = fmt.Sprintf("property %s:%d", file, line)
+	}
+	c.props.Set(key, value, conf.From(origin))
 }
 
 // Accept register bean to Ioc container.
@@ -382,6 +404,10 @@ func (c *container) refresh(autoClear bool) (err error) {
 
 	c.Object(c).Export((*Context)(nil))
 
+	if err = c.postProcess(); err != nil {
+		return err
+	}
+
 	for key, f := range c.mapOfOnProperty {
 		t := reflect.TypeOf(f)
 		in := reflect.New(t.In(0)).Elem()
@@ -457,6 +483,8 @@ func (c *container) refresh(autoClear bool) (err error) {
 
 	cost := time.Now().Sub(start)
 	c.logger.Info(fmt.Sprintf("refresh %d beans cost %v", len(beansById), cost))
+	refreshSeconds.Set(cost.Seconds())
+	refreshBeans.Set(float64(len(beansById)))
 
 	if autoClear && !c.contextAware {
 		c.clear()
@@ -469,6 +497,9 @@ func (c *container) refresh(autoClear bool) (err error) {
 func (c *container) registerBean(b *BeanDefinition) {
 	c.logger.Debug(fmt.Sprintf("register %s name:%q type:%q %s", b.getClass(), b.BeanName(), b.Type(), b.FileLine()))
 	c.beansByName[b.name] = append(c.beansByName[b.name], b)
+	for _, alias := range b.aliases {
+		c.beansByName[alias] = append(c.beansByName[alias], b)
+	}
 	c.beansByType[b.Type()] = append(c.beansByType[b.Type()], b)
 	for _, t := range b.exports {
 		c.logger.Debug(fmt.Sprintf("register %s name:%q type:%q %s", b.getClass(), b.BeanName(), t, b.FileLine()))
@@ -485,6 +516,9 @@ func (c *container) resolveBean(b *BeanDefinition) error {
 
 	b.status = Resolving
 
+	c.resolving = append(c.resolving, b)
+	defer func() { c.resolving = c.resolving[:len(c.resolving)-1] }()
+
 	// method bean 先确定 parent bean 是否存在
 	if b.method {
 		selector, ok := b.f.Parent()
@@ -523,13 +557,23 @@ func (c *container) resolveBean(b *BeanDefinition) error {
 
 // wireTag 注入语法的 tag 分解式，字符串形式的完整格式为 TypeName:BeanName? 。
 // 注入语法的字符串表示形式分为三个部分，TypeName 是原始类型的全限定名，BeanName
-// 是 bean 注册时设置的名称，? 表示注入结果允许为空。
+// 是 bean 注册时设置的名称或者别名，? 表示注入结果允许为空。BeanName 也可以是
+// #Label 形式，表示选择带有该标签的 bean，在收集模式下会选中所有带有该标签的 bean。
 type wireTag struct {
 	typeName string
 	beanName string
+	label    string
 	nullable bool
 }
 
+// match 判断 bean 是否与 tag 相匹配。
+func (tag wireTag) match(b *BeanDefinition) bool {
+	if !b.Match(tag.typeName, tag.beanName) {
+		return false
+	}
+	return tag.label == "" || b.HasLabel(tag.label)
+}
+
 func parseWireTag(str string) (tag wireTag) {
 
 	if str == "" {
@@ -544,11 +588,15 @@ func parseWireTag(str string) (tag wireTag) {
 	i := strings.Index(str, ":")
 	if i < 0 {
 		tag.beanName = str
-		return
+	} else {
+		tag.typeName = str[:i]
+		tag.beanName = str[i+1:]
 	}
 
-	tag.typeName = str[:i]
-	tag.beanName = str[i+1:]
+	if strings.HasPrefix(tag.beanName, "#") {
+		tag.label = tag.beanName[1:]
+		tag.beanName = ""
+	}
 	return
 }
 
@@ -559,6 +607,10 @@ func (tag wireTag) String() string {
 		b.WriteString(":")
 	}
 	b.WriteString(tag.beanName)
+	if tag.label != "" {
+		b.WriteString("#")
+		b.WriteString(tag.label)
+	}
 	if tag.nullable {
 		b.WriteString("?")
 	}
@@ -589,16 +641,39 @@ func toWireString(tags []wireTag) string {
 	return buf.String()
 }
 
+// resolvingCycle 返回正在决议的 bean 所形成的条件循环，b 是当前条件所依赖的 bean。
+func (c *container) resolvingCycle(b *BeanDefinition) error {
+	var path []string
+	for i := len(c.resolving) - 1; i >= 0; i-- {
+		path = append([]string{c.resolving[i].ID()}, path...)
+		if c.resolving[i] == b {
+			break
+		}
+	}
+	path = append(path, b.ID())
+	return fmt.Errorf("found condition cycle: %s", strings.Join(path, " -> "))
+}
+
 // findBean 查找符合条件的 bean 对象，注意该函数只能保证返回的 bean 是有效的，
 // 即未被标记为删除的，而不能保证已经完成属性绑定和依赖注入。
+//
+// bean 的条件按照条件之间的依赖关系进行决议，被条件查找的 bean 总是先于条件所属的
+// bean 完成决议，因此决议结果与注册顺序无关。bean 的条件不会查找到其自身，无论按名称
+// 还是按类型查找，条件之间形成循环依赖时都返回错误，例如多个互为 OnMissingBean 的默认 bean。
 func (c *container) findBean(selector BeanSelector) ([]*BeanDefinition, error) {
 
 	finder := func(fn func(*BeanDefinition) bool) ([]*BeanDefinition, error) {
 		var result []*BeanDefinition
 		for _, b := range c.beans {
-			if b.status == Resolving || b.status == Deleted || !fn(b) {
+			if b.status == Deleted || !fn(b) {
 				continue
 			}
+			if b.status == Resolving {
+				if n := len(c.resolving); n > 0 && c.resolving[n-1] == b {
+					continue
+				}
+				return nil, c.resolvingCycle(b)
+			}
 			if err := c.resolveBean(b); err != nil {
 				return nil, err
 			}
@@ -615,7 +690,7 @@ func (c *container) findBean(selector BeanSelector) ([]*BeanDefinition, error) {
 	case string, BeanDefinition, *BeanDefinition:
 		tag := toWireTag(selector)
 		return finder(func(b *BeanDefinition) bool {
-			return b.Match(tag.typeName, tag.beanName)
+			return tag.match(b)
 		})
 	case reflect.Type:
 		t = st
@@ -953,7 +1028,7 @@ func (c *container) getBean(v reflect.Value, tag wireTag, stack *wiringStack) er
 		if b.status == Deleted {
 			continue
 		}
-		if !b.Match(tag.typeName, tag.beanName) {
+		if !tag.match(b) {
 			continue
 		}
 		foundBeans = append(foundBeans, b)
@@ -968,7 +1043,7 @@ func (c *container) getBean(v reflect.Value, tag wireTag, stack *wiringStack) er
 			if !b.Type().AssignableTo(t) {
 				continue
 			}
-			if !b.Match(tag.typeName, tag.beanName) {
+			if !tag.match(b) {
 				continue
 			}
 
@@ -1037,35 +1112,35 @@ func (c *container) getBean(v reflect.Value, tag wireTag, stack *wiringStack) er
 	return nil
 }
 
-// filterBean 返回 tag 对应的 bean 在数组中的索引，找不到返回 -1。
-func filterBean(beans []*BeanDefinition, tag wireTag, t reflect.Type) (int, error) {
+// filterBean 返回 tag 对应的 bean 在数组中的索引，找不到返回 nil。标签形式的 tag
+// 可以对应多个 bean，其他形式的 tag 只能对应一个 bean。
+func filterBean(beans []*BeanDefinition, tag wireTag, t reflect.Type) ([]int, error) {
 
 	var found []int
 	for i, b := range beans {
-		if b.Match(tag.typeName, tag.beanName) {
+		if tag.match(b) {
 			found = append(found, i)
 		}
 	}
 
-	if len(found) > 1 {
+	if len(found) > 1 && tag.label == "" {
 		msg := fmt.Sprintf("found %d beans, bean:%q type:%q [", len(found), tag, t)
 		for _, i := range found {
 			msg += "( " + beans[i].String() + " ), "
 		}
 		msg = msg[:len(msg)-2] + "]"
-		return -1, errors.New(msg)
+		return nil, errors.New(msg)
 	}
 
 	if len(found) > 0 {
-		i := found[0]
-		return i, nil
+		return found, nil
 	}
 
 	if tag.nullable {
-		return -1, nil
+		return nil, nil
 	}
 
-	return -1, fmt.Errorf("can't find bean, bean:%q type:%q", tag, t)
+	return nil, fmt.Errorf("can't find bean, bean:%q type:%q", tag, t)
 }
 
 type byOrder []*BeanDefinition
@@ -1124,22 +1199,28 @@ func (c *container) collectBeans(v reflect.Value, tags []wireTag, nullable bool,
 				continue
 			}
 
-			index, err := filterBean(beans, item, et)
+			indexes, err := filterBean(beans, item, et)
 			if err != nil {
 				return err
 			}
-			if index < 0 {
+			if len(indexes) == 0 {
 				continue
 			}
 
-			if foundAny {
-				afterAny = append(afterAny, beans[index])
-			} else {
-				beforeAny = append(beforeAny, beans[index])
+			var tmpBeans []*BeanDefinition
+			for i, b := range beans {
+				if len(indexes) > 0 && indexes[0] == i {
+					if foundAny {
+						afterAny = append(afterAny, b)
+					} else {
+						beforeAny = append(beforeAny, b)
+					}
+					indexes = indexes[1:]
+					continue
+				}
+				tmpBeans = append(tmpBeans, b)
 			}
-
-			tmpBeans := append([]*BeanDefinition{}, beans[:index]...)
-			beans = append(tmpBeans, beans[index+1:]...)
+			beans = tmpBeans
 		}
 
 		if foundAny {
@@ -1194,9 +1275,14 @@ func (c *container) collectBeans(v reflect.Value, tags []wireTag, nullable bool,
 func (c *container) Close() {
 	// send a cancel signal to all coroutines managed by the IoC container and wait for them to complete their exit.
 	c.cancel()
-	c.wg.Wait()
 
-	c.logger.Info("goroutines exited")
+	if running := c.waitGoroutines(); len(running) > 0 {
+		for _, g := range running {
+			c.logger.Error("goroutine didn't exit", slog.String("name", g.Name), slog.Duration("age", g.Age()))
+		}
+	} else {
+		c.logger.Info("goroutines exited")
+	}
 
 	for _, bean := range c.Dependencies(false) {
 		bean.destructor()
@@ -1204,17 +1290,3 @@ func (c *container) Close() {
 
 	c.logger.Info("container closed")
 }
-
-// Go start a goroutine managed by the IoC container.
-func (c *container) Go(fn func(ctx context.Context)) {
-	c.wg.Add(1)
-	go func() {
-		defer c.wg.Done()
-		defer func() {
-			if r := recover(); r != nil {
-				c.logger.Error(fmt.Sprint(r), slog.String("stack", string(debug.Stack())))
-			}
-		}()
-		fn(c.ctx)
-	}()
-}
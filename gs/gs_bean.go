@@ -88,6 +88,8 @@ type BeanDefinition struct {
 	line int    // 注册点所在行数
 
 	name    string         // 名称
+	aliases []string       // 别名
+	labels  []string       // 标签
 	status  beanStatus     // 状态
 	primary bool           // 是否为主版本
 	method  bool           // 是否为成员方法
@@ -170,6 +172,13 @@ func (d *BeanDefinition) Match(typeName string, beanName string) bool {
 	nameIsSame := false
 	if beanName == "" || d.name == beanName {
 		nameIsSame = true
+	} else {
+		for _, alias := range d.aliases {
+			if alias == beanName {
+				nameIsSame = true
+				break
+			}
+		}
 	}
 
 	return typeIsSame && nameIsSame
@@ -181,6 +190,38 @@ func (d *BeanDefinition) Name(name string) *BeanDefinition {
 	return d
 }
 
+// Alias Add alias names for a bean, a bean can be selected by its name or any of its aliases.
+func (d *BeanDefinition) Alias(names ...string) *BeanDefinition {
+	d.aliases = append(d.aliases, names...)
+	return d
+}
+
+// Aliases Return the alias names of the bean.
+func (d *BeanDefinition) Aliases() []string {
+	return d.aliases
+}
+
+// Label Add qualifier labels for a bean, beans can be selected by label with `#label` syntax.
+func (d *BeanDefinition) Label(labels ...string) *BeanDefinition {
+	d.labels = append(d.labels, labels...)
+	return d
+}
+
+// Labels Return the qualifier labels of the bean.
+func (d *BeanDefinition) Labels() []string {
+	return d.labels
+}
+
+// HasLabel Return whether the bean has the label.
+func (d *BeanDefinition) HasLabel(label string) bool {
+	for _, l := range d.labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 // On Set the condition for a bean.
 func (d *BeanDefinition) On(c cond.Condition) *BeanDefinition {
 	if nil == d.cond {
@@ -198,6 +239,11 @@ func (d *BeanDefinition) Order(order float32) *BeanDefinition {
 	return d
 }
 
+// GetOrder Return the sorting order of the bean.
+func (d *BeanDefinition) GetOrder() float32 {
+	return d.order
+}
+
 // DependsOn Set the indirect dependencies for a bean.
 func (d *BeanDefinition) DependsOn(selectors ...BeanSelector) *BeanDefinition {
 	d.depends = append(d.depends, selectors...)
@@ -210,6 +256,21 @@ func (d *BeanDefinition) Primary() *BeanDefinition {
 	return d
 }
 
+// IsPrimary Return whether the bean is marked primary.
+func (d *BeanDefinition) IsPrimary() bool {
+	return d.primary
+}
+
+// Condition Return the condition of the bean, nil if it has none.
+func (d *BeanDefinition) Condition() cond.Condition {
+	return d.cond
+}
+
+// Exports Return the interface types exported by the bean.
+func (d *BeanDefinition) Exports() []reflect.Type {
+	return d.exports
+}
+
 // validLifeCycleFunc 判断是否是合法的用于 bean 生命周期控制的函数，生命周期函数
 // 的要求：只能有一个入参并且必须是 bean 的类型，没有返回值或者只返回 error 类型值。
 func validLifeCycleFunc(fnType reflect.Type, beanValue reflect.Value) bool {
@@ -236,7 +236,7 @@ func TestConstructorBean(t *testing.T) {
 	}, "constructor should be func\\(...\\)bean or func\\(...\\)\\(bean, error\\)")
 }
 
-type Runner interface {
+type runnable interface {
 	Run()
 }
 
@@ -262,7 +262,7 @@ func TestInterface(t *testing.T) {
 		fmt.Println(fnValue.Type())
 		retValue := fnValue.Call([]reflect.Value{})[0]
 		fmt.Println(retValue.Type(), retValue.Elem().Type())
-		r := new(Runner)
+		r := new(runnable)
 		fmt.Println(reflect.TypeOf(r).Elem())
 		ok := retValue.Elem().Type().AssignableTo(reflect.TypeOf(r).Elem())
 		fmt.Println(ok)
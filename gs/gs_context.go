@@ -34,6 +34,11 @@ func (c *container) Has(key string) bool {
 	return c.p.Has(key)
 }
 
+// Origin returns where the property comes from, see conf.Properties.Origin.
+func (c *container) Origin(key string) string {
+	return c.p.Origin(key)
+}
+
 func (c *container) Prop(key string, opts ...conf.GetOption) string {
 	return c.p.Get(key, opts...)
 }
@@ -46,6 +51,21 @@ func (c *container) Bind(i interface{}, args ...conf.BindArg) error {
 	return c.p.Bind(i, args...)
 }
 
+// Beans returns the bean definitions which are not deleted in registration order, the
+// IoC container must not be auto cleared, i.e. `gs.Context` should be injected by autowire tag.
+func (c *container) Beans() []*BeanDefinition {
+	if nil == c.tempContainer {
+		return nil
+	}
+	var beans []*BeanDefinition
+	for _, b := range c.beans {
+		if b.status != Deleted {
+			beans = append(beans, b)
+		}
+	}
+	return beans
+}
+
 // Find the bean objects that meet the specified conditions. Note that this function can only guarantee that the returned beans are valid, i.e.,
 // not marked for deletion, but it cannot guarantee that property binding and dependency injection have been completed.
 func (c *container) Find(selector BeanSelector) ([]utils.BeanDefinition, error) {
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"go-spring.dev/spring/conf"
+)
+
+// RestartPolicy decides whether a goroutine managed by the IoC container restarts after a panic.
+type RestartPolicy int
+
+const (
+	RestartNever     = RestartPolicy(iota) // the goroutine exits after a panic.
+	RestartOnFailure                       // the goroutine restarts after a panic with backoff.
+)
+
+// GoOption configures a goroutine managed by the IoC container.
+type GoOption func(g *goroutine)
+
+// GoName sets the name of the goroutine, it defaults to the name of the function.
+func GoName(name string) GoOption {
+	return func(g *goroutine) {
+		g.name = name
+	}
+}
+
+// GoRestart sets the restart policy of the goroutine, it defaults to RestartNever.
+func GoRestart(policy RestartPolicy) GoOption {
+	return func(g *goroutine) {
+		g.policy = policy
+	}
+}
+
+// GoBackoff sets the delay before the first restart, the delay doubles on each
+// restart until max. It defaults to 1s and 1m.
+func GoBackoff(initial, max time.Duration) GoOption {
+	return func(g *goroutine) {
+		g.backoff = initial
+		g.maxBackoff = max
+	}
+}
+
+// GoroutineInfo describes a running goroutine managed by the IoC container.
+type GoroutineInfo struct {
+	Name     string
+	Started  time.Time
+	Restarts int
+}
+
+// Age returns how long the goroutine has been running since it was first started.
+func (g GoroutineInfo) Age() time.Duration {
+	return time.Since(g.Started)
+}
+
+type goroutine struct {
+	id         uint64
+	name       string
+	policy     RestartPolicy
+	backoff    time.Duration
+	maxBackoff time.Duration
+	started    time.Time
+	restarts   int
+}
+
+func funcName(fn interface{}) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "goroutine"
+	}
+	name := f.Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// Go start a goroutine managed by the IoC container, the goroutine receives the
+// context of the container which is canceled when the container closes.
+func (c *container) Go(fn func(ctx context.Context), opts ...GoOption) {
+
+	g := &goroutine{
+		name:       funcName(fn),
+		policy:     RestartNever,
+		backoff:    time.Second,
+		maxBackoff: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	c.goMutex.Lock()
+	c.goSeq++
+	g.id = c.goSeq
+	g.started = time.Now()
+	c.goroutines[g.id] = g
+	c.goMutex.Unlock()
+
+	c.wg.Add(1)
+	goroutinesRunning.Inc()
+	go func() {
+		defer func() {
+			c.goMutex.Lock()
+			delete(c.goroutines, g.id)
+			c.goMutex.Unlock()
+			goroutinesRunning.Dec()
+			c.wg.Done()
+		}()
+
+		backoff := g.backoff
+		for c.runGoroutine(g, fn) && g.policy == RestartOnFailure {
+			c.logger.Warn("goroutine will restart", slog.String("name", g.name), slog.Duration("backoff", backoff))
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > g.maxBackoff {
+				backoff = g.maxBackoff
+			}
+			c.goMutex.Lock()
+			g.restarts++
+			c.goMutex.Unlock()
+			goroutineRestarts.With(g.name).Inc()
+		}
+	}()
+}
+
+// runGoroutine runs fn and returns whether it panicked.
+func (c *container) runGoroutine(g *goroutine, fn func(ctx context.Context)) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			goroutinePanics.With(g.name).Inc()
+			c.logger.Error(fmt.Sprint(r), slog.String("goroutine", g.name), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+	fn(c.ctx)
+	return false
+}
+
+// Goroutines returns the running goroutines managed by the IoC container, sorted by start time.
+func (c *container) Goroutines() []GoroutineInfo {
+	c.goMutex.Lock()
+	defer c.goMutex.Unlock()
+
+	ids := make([]uint64, 0, len(c.goroutines))
+	for id := range c.goroutines {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	infos := make([]GoroutineInfo, 0, len(ids))
+	for _, id := range ids {
+		g := c.goroutines[id]
+		infos = append(infos, GoroutineInfo{Name: g.name, Started: g.started, Restarts: g.restarts})
+	}
+	return infos
+}
+
+// waitGoroutines waits for the goroutines managed by the IoC container to exit at most
+// `spring.goroutines.close-timeout`, a non-positive timeout waits forever. It returns
+// the goroutines that haven't exited yet.
+func (c *container) waitGoroutines() []GoroutineInfo {
+
+	var timeout time.Duration
+	if err := c.p.Bind(&timeout, conf.Key("spring.goroutines.close-timeout:=30s")); err != nil {
+		c.logger.Error("invalid goroutines close timeout", slog.Any("err", err))
+		timeout = 30 * time.Second
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return c.Goroutines()
+	}
+}
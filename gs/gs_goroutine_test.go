@@ -0,0 +1,100 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestContainer_Go(t *testing.T) {
+
+	t.Run("named", func(t *testing.T) {
+		c := New().(*container)
+		err := c.Refresh()
+		assert.Nil(t, err)
+
+		c.Go(func(ctx context.Context) { <-ctx.Done() }, GoName("worker"))
+		c.Go(func(ctx context.Context) { <-ctx.Done() })
+
+		infos := c.Goroutines()
+		assert.Equal(t, len(infos), 2)
+		assert.Equal(t, infos[0].Name, "worker")
+		assert.String(t, infos[1].Name).HasPrefix("gs.TestContainer_Go")
+		assert.True(t, infos[0].Age() >= 0)
+
+		c.Close()
+		assert.Equal(t, len(c.Goroutines()), 0)
+	})
+
+	t.Run("restart on failure", func(t *testing.T) {
+		c := New().(*container)
+		err := c.Refresh()
+		assert.Nil(t, err)
+
+		var count atomic.Int32
+		done := make(chan struct{})
+		c.Go(func(ctx context.Context) {
+			if count.Add(1) < 3 {
+				panic("failed")
+			}
+			close(done)
+			<-ctx.Done()
+		}, GoName("restart"), GoRestart(RestartOnFailure), GoBackoff(time.Millisecond, 2*time.Millisecond))
+
+		<-done
+		infos := c.Goroutines()
+		assert.Equal(t, len(infos), 1)
+		assert.Equal(t, infos[0].Restarts, 2)
+		c.Close()
+	})
+
+	t.Run("never restart", func(t *testing.T) {
+		c := New().(*container)
+		err := c.Refresh()
+		assert.Nil(t, err)
+
+		var count atomic.Int32
+		c.Go(func(ctx context.Context) {
+			count.Add(1)
+			panic("failed")
+		})
+		c.Close()
+		assert.Equal(t, count.Load(), int32(1))
+	})
+
+	t.Run("close timeout", func(t *testing.T) {
+		c := New().(*container)
+		c.Properties().Set("spring.goroutines.close-timeout", "10ms")
+		err := c.Refresh()
+		assert.Nil(t, err)
+
+		exit := make(chan struct{})
+		defer close(exit)
+		c.Go(func(ctx context.Context) { <-exit }, GoName("stuck"))
+		c.Go(func(ctx context.Context) { <-ctx.Done() }, GoName("polite"))
+
+		c.cancel()
+		running := c.waitGoroutines()
+		assert.Equal(t, len(running), 1)
+		assert.Equal(t, running[0].Name, "stuck")
+	})
+}
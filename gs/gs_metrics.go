@@ -0,0 +1,44 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/metrics"
+)
+
+func init() {
+	AutoConfiguration("metrics", func(r BeanDefinitionRegistry) error {
+		r.Object(metrics.Default).
+			On(cond.OnProperty("spring.metrics.enabled", cond.HavingValue("true"), cond.MatchIfMissing())).
+			On(cond.OnMissingBean((*metrics.Registry)(nil)))
+		return nil
+	})
+}
+
+var (
+	refreshSeconds = metrics.Default.Gauge("gs_container_refresh_seconds",
+		"The duration of the last refresh of the IoC container.").With()
+	refreshBeans = metrics.Default.Gauge("gs_container_beans",
+		"The number of beans in the IoC container after the last refresh.").With()
+	goroutinesRunning = metrics.Default.Gauge("gs_goroutines",
+		"The number of running goroutines managed by the IoC container.").With()
+	goroutinePanics = metrics.Default.Counter("gs_goroutine_panics_total",
+		"The number of panics of goroutines managed by the IoC container.", "name")
+	goroutineRestarts = metrics.Default.Counter("gs_goroutine_restarts_total",
+		"The number of restarts of goroutines managed by the IoC container.", "name")
+)
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/spring/metrics"
+)
+
+func TestContainerMetrics(t *testing.T) {
+
+	c := New().(*container)
+	c.Object(&BeanZero{1})
+	assert.Nil(t, c.Refresh())
+	assert.True(t, refreshBeans.Value() >= 2)
+
+	panics := goroutinePanics.With("metrics-worker").Value()
+	done := make(chan struct{})
+	c.Go(func(ctx context.Context) {
+		defer close(done)
+		panic("boom")
+	}, GoName("metrics-worker"))
+	<-done
+	c.Close()
+	assert.Equal(t, goroutinePanics.With("metrics-worker").Value(), panics+1)
+
+	var buf bytes.Buffer
+	assert.Nil(t, metrics.Default.WriteText(&buf))
+	assert.True(t, strings.Contains(buf.String(), "# TYPE gs_container_refresh_seconds gauge"))
+	assert.True(t, strings.Contains(buf.String(), `gs_goroutine_panics_total{name="metrics-worker"}`))
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/dync"
+	"go-spring.dev/spring/gs/arg"
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/internal/utils"
+)
+
+// Module is a named and reusable bundle of bean definitions, default properties and a
+// condition, which is installed on an App or a container explicitly, unlike the
+// auto-configurations applied to every App.
+//
+//	var WebModule = gs.NewModule("web", func(r gs.BeanDefinitionRegistry) error {
+//		r.Object(new(Server))
+//		return nil
+//	}).Requires(MetricsModule).Property("http.addr", ":8080")
+//
+//	app.Install(WebModule)
+type Module struct {
+	name       string
+	requires   []*Module
+	properties map[string]string
+	cond       cond.Condition
+	setup      func(r BeanDefinitionRegistry) error
+}
+
+// NewModule returns a module whose bean definitions are registered by setup.
+func NewModule(name string, setup func(r BeanDefinitionRegistry) error) *Module {
+	return &Module{name: name, setup: setup, properties: make(map[string]string)}
+}
+
+// Name Return the name of the module.
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Requires the modules are installed before this one, and installed together with it.
+func (m *Module) Requires(modules ...*Module) *Module {
+	m.requires = append(m.requires, modules...)
+	return m
+}
+
+// Property set the default value of a property, which is used when the property isn't configured.
+func (m *Module) Property(key, value string) *Module {
+	m.properties[key] = value
+	return m
+}
+
+// On the beans of the module are registered only when the condition matches, and the
+// default properties are used only when the condition matches the properties when the
+// module is installed, so the condition of a module with default properties can't
+// depend on beans.
+func (m *Module) On(c cond.Condition) *Module {
+	if m.cond == nil {
+		m.cond = c
+	} else {
+		m.cond = cond.On(m.cond).On(c)
+	}
+	return m
+}
+
+// Install the modules are installed at the start of refresh, before the post-processors.
+func (c *container) Install(modules ...*Module) {
+	if c.state >= Refreshing {
+		panic(errors.New("should call before Refresh"))
+	}
+	c.modules = append(c.modules, modules...)
+}
+
+// installModules install the modules and the modules they require, each module once and
+// after the modules it requires, it returns the installed modules.
+func installModules(r BeanDefinitionRegistry, modules []*Module) ([]*Module, error) {
+
+	var (
+		sorted   []*Module
+		visiting = make(map[*Module]bool)
+		visited  = make(map[*Module]bool)
+		byName   = make(map[string]*Module)
+	)
+
+	var visit func(m *Module, path []string) error
+	visit = func(m *Module, path []string) error {
+		if visited[m] {
+			return nil
+		}
+		path = append(path, m.name)
+		if visiting[m] {
+			return fmt.Errorf("found cycle between modules %v", path)
+		}
+		if other, ok := byName[m.name]; ok && other != m {
+			return fmt.Errorf("duplicate module %q", m.name)
+		}
+		byName[m.name] = m
+		visiting[m] = true
+		for _, dep := range m.requires {
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		visiting[m] = false
+		visited[m] = true
+		sorted = append(sorted, m)
+		return nil
+	}
+
+	for _, m := range modules {
+		if err := visit(m, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range sorted {
+		if err := m.install(r); err != nil {
+			return nil, fmt.Errorf("module %q error: %w", m.name, err)
+		}
+		GetLogger().Info("module installed", slog.String("name", m.name))
+	}
+	return sorted, nil
+}
+
+func (m *Module) install(r BeanDefinitionRegistry) error {
+	if err := m.setProperties(r.Properties()); err != nil {
+		return err
+	}
+	if m.setup == nil {
+		return nil
+	}
+	if m.cond != nil {
+		r = &moduleRegistry{BeanDefinitionRegistry: r, cond: m.cond}
+	}
+	return m.setup(r)
+}
+
+// setProperties sets the default properties which aren't configured, when the condition
+// of the module matches.
+func (m *Module) setProperties(p *dync.Properties) error {
+	if len(m.properties) == 0 {
+		return nil
+	}
+	if m.cond != nil {
+		if ok, err := m.cond.Matches(propertyContext{p}); err != nil || !ok {
+			return err
+		}
+	}
+	keys := make([]string, 0, len(m.properties))
+	for key := range m.properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if p.Has(key) {
+			continue
+		}
+		if err := p.Set(key, m.properties[key], conf.From("module "+m.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertyContext evaluates the condition of a module on the properties, the beans
+// aren't resolved when the module is installed.
+type propertyContext struct {
+	p *dync.Properties
+}
+
+func (c propertyContext) Has(key string) bool {
+	return c.p.Has(key)
+}
+
+func (c propertyContext) Prop(key string, opts ...conf.GetOption) string {
+	return c.p.Get(key, opts...)
+}
+
+func (c propertyContext) Find(selector BeanSelector) ([]utils.BeanDefinition, error) {
+	return nil, fmt.Errorf("can't find bean %v before the beans are resolved", selector)
+}
+
+// moduleRegistry registers the bean definitions with the condition of the module.
+type moduleRegistry struct {
+	BeanDefinitionRegistry
+	cond cond.Condition
+}
+
+func (r *moduleRegistry) Accept(b *BeanDefinition) *BeanDefinition {
+	return r.BeanDefinitionRegistry.Accept(b).On(r.cond)
+}
+
+func (r *moduleRegistry) Object(i interface{}) *BeanDefinition {
+	return r.BeanDefinitionRegistry.Object(i).On(r.cond)
+}
+
+func (r *moduleRegistry) Provide(ctor interface{}, args ...arg.Arg) *BeanDefinition {
+	return r.BeanDefinitionRegistry.Provide(ctor, args...).On(r.cond)
+}
+
+func (r *moduleRegistry) Configuration(i interface{}) *BeanDefinition {
+	return r.BeanDefinitionRegistry.Configuration(i).On(r.cond)
+}
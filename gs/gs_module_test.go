@@ -0,0 +1,146 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+type moduleDataSource struct {
+	URL string `value:"${db.url}"`
+}
+
+type moduleRepository struct {
+	DataSource *moduleDataSource `autowire:""`
+}
+
+func TestModule(t *testing.T) {
+
+	var installed []string
+	db := NewModule("db", func(r BeanDefinitionRegistry) error {
+		installed = append(installed, "db")
+		r.Object(new(moduleDataSource))
+		return nil
+	}).Property("db.url", "mysql://localhost")
+	repo := NewModule("repo", func(r BeanDefinitionRegistry) error {
+		installed = append(installed, "repo")
+		r.Object(new(moduleRepository))
+		return nil
+	}).Requires(db)
+
+	t.Run("requires", func(t *testing.T) {
+		installed = nil
+		c := New()
+		c.Install(repo, db)
+		err := runTest(c, func(ctx Context) {
+			var r *moduleRepository
+			assert.Nil(t, ctx.Get(&r))
+			assert.Equal(t, r.DataSource.URL, "mysql://localhost")
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, installed, []string{"db", "repo"})
+	})
+
+	t.Run("property", func(t *testing.T) {
+		c := New()
+		p := conf.New()
+		assert.Nil(t, p.Set("db.url", "mysql://db"))
+		assert.Nil(t, c.Properties().Refresh(p))
+		c.Install(repo)
+		err := runTest(c, func(ctx Context) {
+			var ds *moduleDataSource
+			assert.Nil(t, ctx.Get(&ds))
+			assert.Equal(t, ds.URL, "mysql://db")
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("condition", func(t *testing.T) {
+		c := New()
+		c.Install(NewModule("cache", func(r BeanDefinitionRegistry) error {
+			r.Object(new(moduleDataSource)).Name("cache")
+			return nil
+		}).On(cond.OnProperty("cache.enabled", cond.HavingValue("true"))).Property("cache.size", "10"))
+		err := runTest(c, func(ctx Context) {
+			var ds *moduleDataSource
+			assert.Error(t, ctx.Get(&ds), "can't find bean")
+			assert.False(t, ctx.Has("cache.size"))
+		})
+		assert.Nil(t, err)
+
+		c = New()
+		p := conf.New()
+		assert.Nil(t, p.Set("cache.enabled", "true"))
+		assert.Nil(t, c.Properties().Refresh(p))
+		c.Install(NewModule("cache", nil).
+			On(cond.OnProperty("cache.enabled", cond.HavingValue("true"))).Property("cache.size", "10"))
+		err = runTest(c, func(ctx Context) {
+			assert.Equal(t, ctx.Prop("cache.size"), "10")
+		})
+		assert.Nil(t, err)
+
+		c = New()
+		c.Install(NewModule("cache", nil).
+			On(cond.OnBean((*moduleDataSource)(nil))).Property("cache.size", "10"))
+		assert.Error(t, c.Refresh(), "module \"cache\" error: can't find bean .* before the beans are resolved")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		a := NewModule("a", nil)
+		b := NewModule("b", nil).Requires(a)
+		a.Requires(b)
+		c := New()
+		c.Install(a)
+		assert.Error(t, c.Refresh(), "found cycle between modules \\[a b a\\]")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		c := New()
+		c.Install(NewModule("a", nil), NewModule("a", nil))
+		assert.Error(t, c.Refresh(), "duplicate module \"a\"")
+	})
+}
+
+func TestApp_WithoutAutoConfigs(t *testing.T) {
+	os.Clearenv()
+
+	assert.True(t, AutoConfigModule("health") == AutoConfigModule("health"))
+	assert.Panic(t, func() { AutoConfigModule("unknown") }, "auto-configuration \"unknown\" not found")
+
+	for _, install := range []bool{false, true} {
+		app := NewApp(WithProperties(conf.New()), WithoutSignals(), WithoutAutoConfigs())
+		if install {
+			app.Install(AutoConfigModule("health"))
+		}
+		type PandoraAware struct{}
+		var found bool
+		app.Provide(func(ctx Context) PandoraAware {
+			var registry *HealthRegistry
+			found = ctx.Get(&registry) == nil
+			return PandoraAware{}
+		})
+		assert.Nil(t, app.Start(context.Background()))
+		assert.Nil(t, app.Stop(context.Background()))
+		assert.Equal(t, found, install)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"errors"
+
+	"go-spring.dev/spring/dync"
+	"go-spring.dev/spring/gs/arg"
+)
+
+// BeanDefinitionRegistry gives access to the bean definitions registered in the IoC container
+// before they are resolved, post-processors can add, remove or modify them.
+type BeanDefinitionRegistry interface {
+	Properties() *dync.Properties
+	BeanDefinitions() []*BeanDefinition
+	Accept(b *BeanDefinition) *BeanDefinition
+	Object(i interface{}) *BeanDefinition
+	Provide(ctor interface{}, args ...arg.Arg) *BeanDefinition
+	Configuration(i interface{}) *BeanDefinition
+	Remove(b *BeanDefinition)
+}
+
+// BeanDefinitionRegistryPostProcessor is invoked at the start of refresh, after all registrations
+// but before any condition is evaluated.
+type BeanDefinitionRegistryPostProcessor interface {
+	PostProcessBeanDefinitionRegistry(registry BeanDefinitionRegistry) error
+}
+
+// FuncPostProcessor is an adapter to use a function as a BeanDefinitionRegistryPostProcessor.
+type FuncPostProcessor func(registry BeanDefinitionRegistry) error
+
+func (fn FuncPostProcessor) PostProcessBeanDefinitionRegistry(registry BeanDefinitionRegistry) error {
+	return fn(registry)
+}
+
+// PostProcessor register a BeanDefinitionRegistryPostProcessor, they are invoked in registration order.
+func (c *container) PostProcessor(p BeanDefinitionRegistryPostProcessor) {
+	if c.state >= Refreshing {
+		panic(errors.New("should call before Refresh"))
+	}
+	c.postProcessors = append(c.postProcessors, p)
+}
+
+// BeanDefinitions return the registered bean definitions in registration order.
+func (c *container) BeanDefinitions() []*BeanDefinition {
+	beans := make([]*BeanDefinition, len(c.beans))
+	copy(beans, c.beans)
+	return beans
+}
+
+// Remove unregister a bean definition from Ioc container.
+func (c *container) Remove(b *BeanDefinition) {
+	if c.state >= Refreshing {
+		panic(errors.New("should call before Refresh"))
+	}
+	for i, bean := range c.beans {
+		if bean == b {
+			c.beans = append(c.beans[:i], c.beans[i+1:]...)
+			return
+		}
+	}
+}
+
+// postProcess install the modules and invoke the registered post-processors, a post-processor
+// can register others.
+func (c *container) postProcess() error {
+	installed, err := installModules(c, c.modules)
+	if err != nil {
+		return err
+	}
+	c.installed = installed
+	for i := 0; i < len(c.postProcessors); i++ {
+		if err := c.postProcessors[i].PostProcessBeanDefinitionRegistry(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"errors"
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestPostProcessor(t *testing.T) {
+
+	t.Run("modify definitions", func(t *testing.T) {
+		c := New()
+		p := conf.New()
+		p.Set("mode", "second")
+		err := c.Properties().Refresh(p)
+		assert.Nil(t, err)
+
+		c.Object(&BeanZero{1}).Name("first")
+		c.Object(&BeanZero{2}).Name("second")
+		c.Object(&BeanZero{3}).Name("third")
+
+		var seen []string
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			mode := r.Properties().Get("mode")
+			for _, b := range r.BeanDefinitions() {
+				seen = append(seen, b.BeanName())
+				switch b.BeanName() {
+				case mode:
+					b.Primary()
+				case "third":
+					r.Remove(b)
+				}
+			}
+			r.Object(&BeanOne{}).On(cond.OnBean("second"))
+			return nil
+		}))
+
+		err = runTest(c, func(ctx Context) {
+			var zero *BeanZero
+			err := ctx.Get(&zero)
+			assert.Nil(t, err)
+			assert.Equal(t, zero.Int, 2)
+
+			err = ctx.Get(&zero, "third")
+			assert.Error(t, err, "can't find bean, bean:\"third\"")
+
+			var one *BeanOne
+			err = ctx.Get(&one)
+			assert.Nil(t, err)
+			assert.Equal(t, one.Zero, zero)
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, seen, []string{"first", "second", "third", "runTest.func1", "container"})
+	})
+
+	t.Run("chained post-processor", func(t *testing.T) {
+		c := New()
+		var called []string
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			called = append(called, "first")
+			c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+				called = append(called, "nested")
+				return nil
+			}))
+			return nil
+		}))
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			called = append(called, "second")
+			return nil
+		}))
+		err := c.Refresh()
+		assert.Nil(t, err)
+		assert.Equal(t, called, []string{"first", "second", "nested"})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := New()
+		c.PostProcessor(FuncPostProcessor(func(r BeanDefinitionRegistry) error {
+			return errors.New("post-process failed")
+		}))
+		err := c.Refresh()
+		assert.Error(t, err, "post-process failed")
+	})
+}
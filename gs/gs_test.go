@@ -3182,3 +3182,156 @@ func TestContextGetWire(t *testing.T) {
 	})
 
 }
+
+func TestApplicationContext_ConditionOrder(t *testing.T) {
+
+	register := []func(c Container){
+		func(c Container) {
+			c.Object(&BeanZero{1}).Name("zero").On(cond.OnMissingBean("default_zero"))
+		},
+		func(c Container) {
+			c.Object(&BeanZero{2}).Name("default_zero").On(cond.OnProperty("default.enable"))
+		},
+		func(c Container) {
+			c.Object(new(BeanOne)).On(cond.OnBean("zero"))
+		},
+	}
+
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		c := New()
+		for _, i := range order {
+			register[i](c)
+		}
+		err := runTest(c, func(ctx Context) {
+			var one *BeanOne
+			err := ctx.Get(&one)
+			assert.Nil(t, err)
+			assert.Equal(t, one.Zero.Int, 1)
+		})
+		assert.Nil(t, err)
+	}
+
+	t.Run("self", func(t *testing.T) {
+		c := New()
+		c.Object(&BeanZero{1}).On(cond.OnMissingBean((*BeanZero)(nil)))
+		err := runTest(c, func(ctx Context) {
+			var zero *BeanZero
+			err := ctx.Get(&zero)
+			assert.Nil(t, err)
+		})
+		assert.Nil(t, err)
+	})
+
+	// the cycles are reported whichever bean is registered first.
+	bothOrders := func(t *testing.T, register func(c Container, first bool), expect string) {
+		for _, first := range []bool{true, false} {
+			c := New()
+			register(c, first)
+			assert.Error(t, c.Refresh(), expect)
+		}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		bothOrders(t, func(c Container, first bool) {
+			a := func() { c.Object(&BeanZero{1}).Name("a").On(cond.OnMissingBean((*BeanZero)(nil))) }
+			b := func() { c.Object(&BeanZero{2}).Name("b").On(cond.OnMissingBean((*BeanZero)(nil))) }
+			if first {
+				a()
+				b()
+			} else {
+				b()
+				a()
+			}
+		}, "found condition cycle: .*BeanZero:[ab] -> .*BeanZero:[ab] -> .*BeanZero:[ab]")
+	})
+
+	t.Run("type", func(t *testing.T) {
+		bothOrders(t, func(c Container, first bool) {
+			x := func() { c.Object(&BeanZero{1}).Name("x").On(cond.OnBean((*BeanTwo)(nil))) }
+			y := func() { c.Object(new(BeanTwo)).Name("y").On(cond.OnMissingBean((*BeanZero)(nil))) }
+			if first {
+				x()
+				y()
+			} else {
+				y()
+				x()
+			}
+		}, "found condition cycle: ")
+	})
+
+	t.Run("name and type", func(t *testing.T) {
+		bothOrders(t, func(c Container, first bool) {
+			a := func() { c.Object(&BeanZero{1}).Name("a").On(cond.OnMissingBean("b")) }
+			b := func() { c.Object(new(BeanTwo)).Name("b").On(cond.OnMissingBean((*BeanZero)(nil))) }
+			if first {
+				a()
+				b()
+			} else {
+				b()
+				a()
+			}
+		}, "found condition cycle: ")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		c := New()
+		c.Object(&BeanZero{1}).Name("a").On(cond.OnMissingBean("b"))
+		c.Object(&BeanZero{2}).Name("b").On(cond.OnMissingBean("a"))
+		err := c.Refresh()
+		assert.Error(t, err, "found condition cycle: .*BeanZero:a -> .*BeanZero:b -> .*BeanZero:a")
+	})
+}
+
+func TestApplicationContext_AliasAndLabel(t *testing.T) {
+
+	type BeanFoo struct{}
+	type BeanBar struct{}
+
+	type HealthCollector struct {
+		All     []*BeanZero          `autowire:"#health"`
+		Ordered []*BeanZero          `autowire:"b,#health"`
+		Named   map[string]*BeanZero `autowire:"#health"`
+		Single  *BeanZero            `autowire:"#single"`
+		Alias   *BeanZero            `autowire:"c_alias"`
+		None    []*BeanZero          `autowire:"#none?"`
+	}
+
+	c := New()
+	c.Object(&BeanZero{1}).Name("a").Label("health").Order(2)
+	c.Object(&BeanZero{2}).Name("b").Label("health", "single").Order(1)
+	c.Object(&BeanZero{3}).Name("c").Alias("c_alias")
+	c.Object(new(BeanBar)).On(cond.OnBean("#health"))
+	c.Object(new(BeanFoo)).On(cond.OnBean("#none"))
+	collector := c.Object(new(HealthCollector))
+
+	err := runTest(c, func(ctx Context) {
+		var zero *BeanZero
+		err := ctx.Get(&zero, "c_alias")
+		assert.Nil(t, err)
+		assert.Equal(t, zero.Int, 3)
+
+		err = ctx.Get(&zero, "#health")
+		assert.Error(t, err, "found 2 beans")
+
+		var bar *BeanBar
+		err = ctx.Get(&bar)
+		assert.Nil(t, err)
+
+		var foo *BeanFoo
+		err = ctx.Get(&foo)
+		assert.Error(t, err, "can't find bean")
+	})
+	assert.Nil(t, err)
+
+	hc := collector.Interface().(*HealthCollector)
+	assert.Equal(t, len(hc.All), 2)
+	assert.Equal(t, hc.All[0].Int, 2)
+	assert.Equal(t, hc.All[1].Int, 1)
+	assert.Equal(t, hc.Ordered[0].Int, 2)
+	assert.Equal(t, hc.Ordered[1].Int, 1)
+	assert.Equal(t, len(hc.Named), 2)
+	assert.Equal(t, hc.Named["a"].Int, 1)
+	assert.Equal(t, hc.Single.Int, 2)
+	assert.Equal(t, hc.Alias.Int, 3)
+	assert.Equal(t, len(hc.None), 0)
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-spring.dev/spring/gs/cond"
+)
+
+func init() {
+	AutoConfiguration("health", func(r BeanDefinitionRegistry) error {
+		r.Object(NewHealthRegistry(0)).
+			On(cond.OnProperty("spring.health.enabled", cond.HavingValue("true"), cond.MatchIfMissing())).
+			On(cond.OnMissingBean((*HealthRegistry)(nil)))
+		return nil
+	})
+}
+
+// HealthState is the state of a component or of the whole application.
+type HealthState string
+
+const (
+	HealthUp       = HealthState("UP")       // the component works.
+	HealthDegraded = HealthState("DEGRADED") // the component works with reduced functionality.
+	HealthDown     = HealthState("DOWN")     // the component doesn't work.
+)
+
+// severity returns the order used to aggregate states, the worst state wins.
+func (s HealthState) severity() int {
+	switch s {
+	case HealthUp:
+		return 0
+	case HealthDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// HealthStatus is the health of a component.
+type HealthStatus struct {
+	State   HealthState            `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthIndicator is implemented by beans reporting the health of a component, the
+// beans must export the interface to be collected, and can be labeled `liveness` or
+// `readiness` to join the groups of the same name.
+//
+//	gs.Object(new(MysqlHealth)).Name("mysql").Label("readiness").Export((*gs.HealthIndicator)(nil))
+type HealthIndicator interface {
+	Health(ctx context.Context) HealthStatus
+}
+
+// HealthIndicatorFunc is an adapter to use a function as a HealthIndicator.
+type HealthIndicatorFunc func(ctx context.Context) HealthStatus
+
+func (fn HealthIndicatorFunc) Health(ctx context.Context) HealthStatus {
+	return fn(ctx)
+}
+
+// HealthReport is the aggregated health of several components.
+type HealthReport struct {
+	State      HealthState             `json:"status"`
+	Components map[string]HealthStatus `json:"components,omitempty"`
+}
+
+// HealthRegistry aggregates the health of the HealthIndicator beans, an indicator which
+// doesn't report in `spring.health.timeout` is considered DOWN.
+type HealthRegistry struct {
+	Indicators map[string]HealthIndicator `autowire:"?"`
+	Liveness   map[string]HealthIndicator `autowire:"#liveness?"`
+	Readiness  map[string]HealthIndicator `autowire:"#readiness?"`
+	Timeout    time.Duration              `value:"${spring.health.timeout:=5s}"`
+
+	mutex  sync.RWMutex
+	groups map[string]map[string]HealthIndicator
+}
+
+// NewHealthRegistry returns a HealthRegistry, a non-positive timeout means no timeout.
+func NewHealthRegistry(timeout time.Duration) *HealthRegistry {
+	return &HealthRegistry{Timeout: timeout}
+}
+
+func (r *HealthRegistry) OnInit(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for name, ind := range r.Liveness {
+		r.addToGroup("liveness", name, ind)
+	}
+	for name, ind := range r.Readiness {
+		r.addToGroup("readiness", name, ind)
+	}
+	return nil
+}
+
+// Register registers an indicator and adds it to the groups.
+func (r *HealthRegistry) Register(name string, indicator HealthIndicator, groups ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.Indicators == nil {
+		r.Indicators = make(map[string]HealthIndicator)
+	}
+	r.Indicators[name] = indicator
+	for _, group := range groups {
+		r.addToGroup(group, name, indicator)
+	}
+}
+
+func (r *HealthRegistry) addToGroup(group, name string, indicator HealthIndicator) {
+	if r.groups == nil {
+		r.groups = make(map[string]map[string]HealthIndicator)
+	}
+	m, ok := r.groups[group]
+	if !ok {
+		m = make(map[string]HealthIndicator)
+		r.groups[group] = m
+	}
+	m[name] = indicator
+}
+
+// Groups returns the names of the groups.
+func (r *HealthRegistry) Groups() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var names []string
+	for name := range r.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Health returns the aggregated health of all indicators.
+func (r *HealthRegistry) Health(ctx context.Context) HealthReport {
+	r.mutex.RLock()
+	indicators := make(map[string]HealthIndicator, len(r.Indicators))
+	for name, ind := range r.Indicators {
+		indicators[name] = ind
+	}
+	r.mutex.RUnlock()
+	return r.check(ctx, indicators)
+}
+
+// Group returns the aggregated health of the indicators in a group, it returns false
+// when the group doesn't exist.
+func (r *HealthRegistry) Group(ctx context.Context, group string) (HealthReport, bool) {
+	r.mutex.RLock()
+	m, ok := r.groups[group]
+	indicators := make(map[string]HealthIndicator, len(m))
+	for name, ind := range m {
+		indicators[name] = ind
+	}
+	r.mutex.RUnlock()
+	if !ok {
+		return HealthReport{}, false
+	}
+	return r.check(ctx, indicators), true
+}
+
+// check runs the indicators concurrently and aggregates their states, the worst state wins.
+func (r *HealthRegistry) check(ctx context.Context, indicators map[string]HealthIndicator) HealthReport {
+
+	type result struct {
+		name   string
+		status HealthStatus
+	}
+
+	ch := make(chan result, len(indicators))
+	for name, ind := range indicators {
+		go func(name string, ind HealthIndicator) {
+			ch <- result{name, r.checkOne(ctx, ind)}
+		}(name, ind)
+	}
+
+	report := HealthReport{State: HealthUp, Components: make(map[string]HealthStatus, len(indicators))}
+	for range indicators {
+		res := <-ch
+		report.Components[res.name] = res.status
+		if res.status.State.severity() > report.State.severity() {
+			report.State = res.status.State
+		}
+	}
+	return report
+}
+
+// checkOne runs an indicator, an indicator which panics or times out is DOWN.
+func (r *HealthRegistry) checkOne(ctx context.Context, ind HealthIndicator) HealthStatus {
+
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	ch := make(chan HealthStatus, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				ch <- HealthStatus{State: HealthDown, Details: map[string]interface{}{"error": fmt.Sprint(e)}}
+			}
+		}()
+		ch <- ind.Health(ctx)
+	}()
+
+	select {
+	case s := <-ch:
+		if s.State == "" {
+			s.State = HealthDown
+		}
+		return s
+	case <-ctx.Done():
+		return HealthStatus{State: HealthDown, Details: map[string]interface{}{"error": ctx.Err().Error()}}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func fixedHealth(state HealthState) HealthIndicator {
+	return HealthIndicatorFunc(func(ctx context.Context) HealthStatus {
+		return HealthStatus{State: state}
+	})
+}
+
+func TestHealthRegistry(t *testing.T) {
+
+	t.Run("empty", func(t *testing.T) {
+		r := NewHealthRegistry(0)
+		assert.Equal(t, r.Health(context.Background()).State, HealthUp)
+		_, ok := r.Group(context.Background(), "liveness")
+		assert.False(t, ok)
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		r := NewHealthRegistry(0)
+		r.Register("a", fixedHealth(HealthUp), "readiness")
+		r.Register("b", fixedHealth(HealthDegraded))
+		assert.Equal(t, r.Health(context.Background()).State, HealthDegraded)
+
+		r.Register("c", fixedHealth(HealthDown), "liveness")
+		report := r.Health(context.Background())
+		assert.Equal(t, report.State, HealthDown)
+		assert.Equal(t, len(report.Components), 3)
+		assert.Equal(t, r.Groups(), []string{"liveness", "readiness"})
+
+		report, ok := r.Group(context.Background(), "readiness")
+		assert.True(t, ok)
+		assert.Equal(t, report, HealthReport{State: HealthUp, Components: map[string]HealthStatus{"a": {State: HealthUp}}})
+	})
+
+	t.Run("timeout & panic", func(t *testing.T) {
+		r := NewHealthRegistry(10 * time.Millisecond)
+		r.Register("slow", HealthIndicatorFunc(func(ctx context.Context) HealthStatus {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			return HealthStatus{State: HealthUp}
+		}))
+		r.Register("panic", HealthIndicatorFunc(func(ctx context.Context) HealthStatus {
+			panic("boom")
+		}))
+		report := r.Health(context.Background())
+		assert.Equal(t, report.State, HealthDown)
+		assert.Equal(t, report.Components["slow"].Details["error"], "context deadline exceeded")
+		assert.Equal(t, report.Components["panic"].Details["error"], "boom")
+	})
+}
+
+type dbHealth struct{ state HealthState }
+
+func (h *dbHealth) Health(ctx context.Context) HealthStatus {
+	return HealthStatus{State: h.state, Details: map[string]interface{}{"db": "mysql"}}
+}
+
+func TestHealthRegistry_Beans(t *testing.T) {
+	c := New()
+	c.Object(&dbHealth{HealthUp}).Name("mysql").Label("readiness").Export((*HealthIndicator)(nil))
+	c.Object(&dbHealth{HealthDegraded}).Name("redis").Label("liveness").Export((*HealthIndicator)(nil))
+	c.Object(NewHealthRegistry(0))
+	err := runTest(c, func(ctx Context) {
+		var r *HealthRegistry
+		assert.Nil(t, ctx.Get(&r))
+		assert.Equal(t, r.Timeout, 5*time.Second)
+		assert.Equal(t, r.Health(ctx.Context()).State, HealthDegraded)
+
+		report, ok := r.Group(ctx.Context(), "readiness")
+		assert.True(t, ok)
+		assert.Equal(t, report.State, HealthUp)
+		assert.Equal(t, report.Components["mysql"].Details["db"], "mysql")
+
+		report, ok = r.Group(ctx.Context(), "liveness")
+		assert.True(t, ok)
+		assert.Equal(t, report.State, HealthDegraded)
+	})
+	assert.Nil(t, err)
+}
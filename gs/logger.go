@@ -17,6 +17,8 @@
 package gs
 
 import (
+	"log/slog"
+
 	"go-spring.dev/spring/internal/log"
 )
 
@@ -45,3 +47,17 @@ func WithLogName(name string) GetLogOption {
 		options.loggerName = name
 	}
 }
+
+// LoggerLevel describes the configured and the effective level of a named logger.
+type LoggerLevel = log.LoggerLevel
+
+// LoggerLevels returns the levels of the named loggers sorted by name.
+func LoggerLevels() []LoggerLevel {
+	return log.Levels()
+}
+
+// SetLoggerLevel overrides the level of a named logger at runtime, a nil level restores
+// the level of its handler. It returns false when the logger doesn't exist.
+func SetLoggerLevel(loggerName string, level *slog.Level) bool {
+	return log.SetLevel(loggerName, level)
+}
@@ -17,9 +17,12 @@
 package log
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"go-spring.dev/spring/internal/utils"
 )
@@ -44,12 +47,14 @@ func init() {
 }
 
 type namedLogger struct {
-	name   string
-	logger *Logger
+	name    string
+	logger  *Logger
+	handler *levelHandler
 }
 
 func SetLogger(loggerName string, logger *Logger, primary ...bool) {
-	named := &namedLogger{name: loggerName, logger: logger}
+	handler := &levelHandler{Handler: logger.Handler()}
+	named := &namedLogger{name: loggerName, logger: slog.New(handler), handler: handler}
 	loggers.Store(loggerName, named)
 
 	if len(primary) > 0 && primary[0] {
@@ -64,3 +69,114 @@ func GetLogger(loggerName string) *Logger {
 	}
 	return nil
 }
+
+// LoggerLevel describes the level of a named logger.
+type LoggerLevel struct {
+	Name       string
+	Primary    bool
+	Configured *slog.Level // the level set by SetLevel, nil means the level of the handler.
+	Effective  slog.Level  // the minimum level which is enabled.
+}
+
+// Levels returns the levels of the named loggers sorted by name.
+func Levels() []LoggerLevel {
+	var primary *namedLogger
+	if l, ok := loggers.Load(""); ok {
+		primary = l.(*namedLogger)
+	}
+	var levels []LoggerLevel
+	loggers.Range(func(key, value any) bool {
+		if key.(string) == "" {
+			return true
+		}
+		named := value.(*namedLogger)
+		levels = append(levels, LoggerLevel{
+			Name:       named.name,
+			Primary:    named == primary,
+			Configured: named.handler.level(),
+			Effective:  named.handler.effective(),
+		})
+		return true
+	})
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Name < levels[j].Name })
+	return levels
+}
+
+// SetLevel overrides the level of a named logger at runtime, a nil level restores the
+// level of its handler. It returns false when the logger doesn't exist.
+func SetLevel(loggerName string, level *slog.Level) bool {
+	l, ok := loggers.Load(loggerName)
+	if !ok {
+		return false
+	}
+	named := l.(*namedLogger)
+	if level == nil {
+		named.handler.override.Store(nil)
+	} else {
+		lvl := *level
+		named.handler.override.Store(&lvl)
+	}
+	return true
+}
+
+// levelHandler is a slog.Handler whose level can be overridden at runtime.
+type levelHandler struct {
+	slog.Handler
+	override atomic.Pointer[slog.Level]
+}
+
+func (h *levelHandler) level() *slog.Level {
+	if l := h.override.Load(); l != nil {
+		lvl := *l
+		return &lvl
+	}
+	return nil
+}
+
+func (h *levelHandler) effective() slog.Level {
+	if l := h.override.Load(); l != nil {
+		return *l
+	}
+	for _, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn} {
+		if h.Handler.Enabled(context.Background(), l) {
+			return l
+		}
+	}
+	return slog.LevelError
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if l := h.override.Load(); l != nil {
+		return level >= *l
+	}
+	return h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &derivedHandler{Handler: h.Handler.WithAttrs(attrs), root: h}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &derivedHandler{Handler: h.Handler.WithGroup(name), root: h}
+}
+
+// derivedHandler shares the level override of the handler it's derived from.
+type derivedHandler struct {
+	slog.Handler
+	root *levelHandler
+}
+
+func (h *derivedHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if l := h.root.override.Load(); l != nil {
+		return level >= *l
+	}
+	return h.Handler.Enabled(ctx, level)
+}
+
+func (h *derivedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &derivedHandler{Handler: h.Handler.WithAttrs(attrs), root: h.root}
+}
+
+func (h *derivedHandler) WithGroup(name string) slog.Handler {
+	return &derivedHandler{Handler: h.Handler.WithGroup(name), root: h.root}
+}
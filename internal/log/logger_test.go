@@ -0,0 +1,56 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestSetLevel(t *testing.T) {
+
+	var buf bytes.Buffer
+	SetLogger("test", slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	logger := GetLogger("test")
+
+	logger.Debug("hidden")
+	assert.Equal(t, buf.Len(), 0)
+
+	debug := slog.LevelDebug
+	assert.True(t, SetLevel("test", &debug))
+	logger.Debug("shown")
+	assert.True(t, bytes.Contains(buf.Bytes(), []byte("msg=shown logger=test")))
+
+	for _, l := range Levels() {
+		if l.Name == "test" {
+			assert.Equal(t, *l.Configured, slog.LevelDebug)
+			assert.Equal(t, l.Effective, slog.LevelDebug)
+		}
+	}
+
+	assert.True(t, SetLevel("test", nil))
+	buf.Reset()
+	logger.Debug("hidden")
+	assert.Equal(t, buf.Len(), 0)
+
+	levels := Levels()
+	assert.Equal(t, levels[len(levels)-1], LoggerLevel{Name: "test", Effective: slog.LevelInfo})
+	assert.False(t, SetLevel("unknown", nil))
+}
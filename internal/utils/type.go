@@ -59,7 +59,8 @@ func TypeName(i interface{}) string {
 }
 
 // A BeanSelector can be the ID of a bean, a `reflect.Type`, a pointer such as
-// `(*error)(nil)`, or a BeanDefinition value.
+// `(*error)(nil)`, or a BeanDefinition value. The name part of an ID can be an
+// alias of the bean, or a label in the form of `#label`.
 type BeanSelector interface{}
 
 // A BeanDefinition describes a bean whose lifecycle is managed by IoC container.
@@ -0,0 +1,308 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package metrics provides counters, gauges and histograms with labels, and exposes
+// them in the Prometheus text format without an external client library.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefBuckets are the default buckets of histograms, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry used by the built-in instrumentation.
+var Default = NewRegistry()
+
+type metricType string
+
+const (
+	counterType   = metricType("counter")
+	gaugeType     = metricType("gauge")
+	histogramType = metricType("histogram")
+)
+
+// Registry holds metric families by name.
+type Registry struct {
+	mutex    sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// family is a metric with all its labeled series.
+type family struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+	fn      func() float64 // the value of a gauge func.
+
+	mutex  sync.RWMutex
+	series map[string]*series
+}
+
+type series struct {
+	values  []string
+	value   atomicFloat
+	buckets []atomic.Uint64
+	count   atomic.Uint64
+}
+
+// atomicFloat is a float64 updated atomically.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+func (f *atomicFloat) Store(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Add(d float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+d)) {
+			return
+		}
+	}
+}
+
+// register returns the family of the name, it panics when the family is registered
+// with another type or other labels.
+func (r *Registry) register(f *family) *family {
+	if !validName(f.name) {
+		panic(fmt.Errorf("invalid metric name %q", f.name))
+	}
+	for _, l := range f.labels {
+		if !validName(l) || strings.HasPrefix(l, "__") || l == "le" {
+			panic(fmt.Errorf("invalid label name %q of metric %q", l, f.name))
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if old, ok := r.families[f.name]; ok {
+		if old.typ != f.typ || strings.Join(old.labels, ",") != strings.Join(f.labels, ",") || old.fn != nil || f.fn != nil {
+			panic(fmt.Errorf("metric %q is already registered differently", f.name))
+		}
+		return old
+	}
+	f.series = make(map[string]*series)
+	r.families[f.name] = f
+	return f
+}
+
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// with returns the series of the label values, it's created on first use.
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Errorf("metric %q expects %d label values but got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	f.mutex.RLock()
+	s, ok := f.series[key]
+	f.mutex.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if s, ok = f.series[key]; ok {
+		return s
+	}
+	s = &series{values: append([]string(nil), values...)}
+	if f.typ == histogramType {
+		s.buckets = make([]atomic.Uint64, len(f.buckets))
+	}
+	f.series[key] = s
+	return s
+}
+
+// sortedSeries returns the series sorted by label values.
+func (f *family) sortedSeries() []*series {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	arr := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		arr = append(arr, s)
+	}
+	sort.Slice(arr, func(i, j int) bool {
+		return strings.Join(arr[i].values, "\xff") < strings.Join(arr[j].values, "\xff")
+	})
+	return arr
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	f *family
+}
+
+// Counter registers a counter, or returns the registered one of the same name.
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{r.register(&family{name: name, help: help, typ: counterType, labels: labels})}
+}
+
+// With returns the counter of the label values.
+func (v *CounterVec) With(values ...string) *Counter {
+	return &Counter{v.f.with(values)}
+}
+
+// Counter is a value which only increases.
+type Counter struct {
+	s *series
+}
+
+// Inc increases the counter by 1.
+func (c *Counter) Inc() {
+	c.s.value.Add(1)
+}
+
+// Add increases the counter by d, it panics when d is negative.
+func (c *Counter) Add(d float64) {
+	if d < 0 {
+		panic(fmt.Errorf("counter can't decrease"))
+	}
+	c.s.value.Add(d)
+}
+
+// Value returns the value of the counter.
+func (c *Counter) Value() float64 {
+	return c.s.value.Load()
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	f *family
+}
+
+// Gauge registers a gauge, or returns the registered one of the same name.
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{r.register(&family{name: name, help: help, typ: gaugeType, labels: labels})}
+}
+
+// GaugeFunc registers a gauge whose value is returned by fn when collected.
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(&family{name: name, help: help, typ: gaugeType, fn: fn})
+}
+
+// With returns the gauge of the label values.
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return &Gauge{v.f.with(values)}
+}
+
+// Gauge is a value which can go up and down.
+type Gauge struct {
+	s *series
+}
+
+// Set sets the value of the gauge.
+func (g *Gauge) Set(v float64) {
+	g.s.value.Store(v)
+}
+
+// Add adds d to the gauge, d can be negative.
+func (g *Gauge) Add(d float64) {
+	g.s.value.Add(d)
+}
+
+// Inc increases the gauge by 1.
+func (g *Gauge) Inc() {
+	g.s.value.Add(1)
+}
+
+// Dec decreases the gauge by 1.
+func (g *Gauge) Dec() {
+	g.s.value.Add(-1)
+}
+
+// Value returns the value of the gauge.
+func (g *Gauge) Value() float64 {
+	return g.s.value.Load()
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	f *family
+}
+
+// Histogram registers a histogram, or returns the registered one of the same name.
+// The buckets are the upper bounds in increasing order, nil means DefBuckets.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Errorf("buckets of histogram %q should be in increasing order", name))
+	}
+	return &HistogramVec{r.register(&family{name: name, help: help, typ: histogramType, labels: labels, buckets: buckets})}
+}
+
+// With returns the histogram of the label values.
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return &Histogram{f: v.f, s: v.f.with(values)}
+}
+
+// Histogram counts observations in buckets.
+type Histogram struct {
+	f *family
+	s *series
+}
+
+// Observe adds an observation.
+func (h *Histogram) Observe(v float64) {
+	if i := sort.SearchFloat64s(h.f.buckets, v); i < len(h.s.buckets) {
+		h.s.buckets[i].Add(1)
+	}
+	h.s.value.Add(v)
+	h.s.count.Add(1)
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	return h.s.count.Load()
+}
+
+// Sum returns the sum of observations.
+func (h *Histogram) Sum() float64 {
+	return h.s.value.Load()
+}
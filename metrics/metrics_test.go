@@ -0,0 +1,108 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestRegistry(t *testing.T) {
+
+	r := NewRegistry()
+
+	requests := r.Counter("http_requests_total", "The number of requests.", "method", "code")
+	requests.With("GET", "200").Inc()
+	requests.With("GET", "200").Add(2)
+	requests.With("POST", "500").Inc()
+	assert.Equal(t, requests.With("GET", "200").Value(), float64(3))
+
+	// registering the same metric returns the registered one.
+	assert.Equal(t, r.Counter("http_requests_total", "", "method", "code").With("GET", "200").Value(), float64(3))
+
+	inflight := r.Gauge("http_inflight", "The number of in-flight requests.").With()
+	inflight.Inc()
+	inflight.Inc()
+	inflight.Dec()
+	assert.Equal(t, inflight.Value(), float64(1))
+
+	r.GaugeFunc("up", "", func() float64 { return 1 })
+
+	latency := r.Histogram("http_latency_seconds", "The latency\nof requests.", []float64{0.1, 1}, "path").With("/a\"b")
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(5)
+	assert.Equal(t, latency.Count(), uint64(3))
+	assert.Equal(t, latency.Sum(), 5.55)
+
+	var buf bytes.Buffer
+	assert.Nil(t, r.WriteText(&buf))
+	assert.Equal(t, buf.String(), `# HELP http_inflight The number of in-flight requests.
+# TYPE http_inflight gauge
+http_inflight 1
+# HELP http_latency_seconds The latency\nof requests.
+# TYPE http_latency_seconds histogram
+http_latency_seconds_bucket{path="/a\"b",le="0.1"} 1
+http_latency_seconds_bucket{path="/a\"b",le="1"} 2
+http_latency_seconds_bucket{path="/a\"b",le="+Inf"} 3
+http_latency_seconds_sum{path="/a\"b"} 5.55
+http_latency_seconds_count{path="/a\"b"} 3
+# HELP http_requests_total The number of requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",code="200"} 3
+http_requests_total{method="POST",code="500"} 1
+# TYPE up gauge
+up 1
+`)
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, w.Header().Get("Content-Type"), ContentType)
+	assert.Equal(t, w.Body.String(), buf.String())
+}
+
+func TestRegistry_Panic(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("total", "", "a")
+	assert.Panic(t, func() { r.Gauge("total", "", "a") }, "metric \"total\" is already registered differently")
+	assert.Panic(t, func() { r.Counter("total", "", "b") }, "metric \"total\" is already registered differently")
+	assert.Panic(t, func() { r.Counter("1total", "") }, "invalid metric name \"1total\"")
+	assert.Panic(t, func() { r.Counter("c", "", "le") }, "invalid label name \"le\" of metric \"c\"")
+	assert.Panic(t, func() { r.Counter("total", "", "a").With() }, "metric \"total\" expects 1 label values but got 0")
+	assert.Panic(t, func() { r.Counter("total", "", "a").With("x").Add(-1) }, "counter can't decrease")
+	assert.Panic(t, func() { r.Histogram("h", "", []float64{2, 1}) }, "buckets of histogram \"h\" should be in increasing order")
+}
+
+func TestCounter_Concurrent(t *testing.T) {
+	c := NewRegistry().Counter("total", "").With()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Add(0.5)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, c.Value(), float64(5000))
+}
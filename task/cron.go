@@ -0,0 +1,298 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes the bounds and the names of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	spec                                  string
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+	location                              *time.Location
+}
+
+// ParseCron parses a cron expression with six fields `second minute hour day-of-month
+// month day-of-week`, the second field can be omitted. Each field supports `*`, `?`,
+// lists `a,b`, ranges `a-b` and steps `*/n` or `a-b/n`, months and days of week can
+// be names such as `JAN` and `MON`. The descriptors `@yearly`, `@monthly`, `@weekly`,
+// `@daily` and `@hourly` are also supported. The expression can be prefixed by
+// `CRON_TZ=<zone>` or `TZ=<zone>` to be evaluated in a time zone, otherwise it's
+// evaluated in the location of the time passed to Next.
+func ParseCron(spec string) (*CronSchedule, error) {
+
+	s := &CronSchedule{spec: spec}
+
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		i := strings.Index(expr, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("parse cron %q error: missing fields", spec)
+		}
+		zone := expr[strings.Index(expr, "=")+1 : i]
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+		}
+		s.location = loc
+		expr = strings.TrimSpace(expr[i:])
+	}
+
+	if d, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("parse cron %q error: expected 5 or 6 fields but got %d", spec, len(fields))
+	}
+
+	var err error
+	if s.second, _, err = parseCronField(fields[0], secondField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+	if s.minute, _, err = parseCronField(fields[1], minuteField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+	if s.hour, _, err = parseCronField(fields[2], hourField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+	if s.dom, s.domStar, err = parseCronField(fields[3], domField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+	if s.month, _, err = parseCronField(fields[4], monthField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+	if s.dow, s.dowStar, err = parseCronField(fields[5], dowField); err != nil {
+		return nil, fmt.Errorf("parse cron %q error: %w", spec, err)
+	}
+
+	// sunday can be 0 or 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField returns the bits of a field, and whether the field is `*` or `?`.
+func parseCronField(expr string, f cronField) (bits uint64, star bool, err error) {
+	for _, part := range strings.Split(expr, ",") {
+		var (
+			lo, hi int
+			step   = 1
+		)
+
+		rangeExpr := part
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangeExpr = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step %q in %s field", part, f.name)
+			}
+		}
+
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+			lo, hi = f.min, f.max
+			if f.max == 7 {
+				hi = 6
+			}
+			star = star || step == 1
+		case strings.Contains(rangeExpr, "-"):
+			i := strings.Index(rangeExpr, "-")
+			if lo, err = f.parseValue(rangeExpr[:i]); err != nil {
+				return 0, false, err
+			}
+			if hi, err = f.parseValue(rangeExpr[i+1:]); err != nil {
+				return 0, false, err
+			}
+		default:
+			if lo, err = f.parseValue(rangeExpr); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if strings.Contains(part, "/") {
+				hi = f.max
+			}
+		}
+
+		if lo > hi {
+			return 0, false, fmt.Errorf("invalid range %q in %s field", part, f.name)
+		}
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, star, nil
+}
+
+func (f cronField) parseValue(s string) (int, error) {
+	if i, ok := f.names[strings.ToLower(s)]; ok {
+		return i, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if i < f.min || i > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d,%d] in %s field", i, f.min, f.max, f.name)
+	}
+	return i, nil
+}
+
+// String returns the cron expression.
+func (s *CronSchedule) String() string {
+	return s.spec
+}
+
+// Next returns the next time matched by the cron expression after t, it returns
+// zero time when no time matches in five years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+
+	origLocation := t.Location()
+	loc := s.location
+	if loc == nil {
+		loc = origLocation
+	}
+	t = t.In(loc)
+
+	// starts from the next second.
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
+
+	added := false
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		// the day may not start at midnight because of daylight saving time.
+		if h := t.Hour(); h != 0 {
+			if h > 12 {
+				t = t.Add(time.Duration(24-h) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-h) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t.In(origLocation)
+}
+
+// dayMatches returns whether t matches the day-of-month and day-of-week fields, when
+// both fields are restricted, either of them matching is enough.
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.dom != 0
+	dowMatch := 1<<uint(t.Weekday())&s.dow != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestParseCron(t *testing.T) {
+
+	t.Run("error", func(t *testing.T) {
+		_, err := ParseCron("* * *")
+		assert.Error(t, err, "expected 5 or 6 fields but got 3")
+		_, err = ParseCron("60 * * * * *")
+		assert.Error(t, err, "value 60 out of range \\[0,59\\] in second field")
+		_, err = ParseCron("0 0 * * FOO")
+		assert.Error(t, err, "invalid value \"FOO\" in day of week field")
+		_, err = ParseCron("*/0 * * * * *")
+		assert.Error(t, err, "invalid step \"\\*/0\" in second field")
+		_, err = ParseCron("0 5-1 * * *")
+		assert.Error(t, err, "invalid range \"5-1\" in hour field")
+		_, err = ParseCron("CRON_TZ=Nowhere/City 0 0 * * *")
+		assert.Error(t, err, "unknown time zone Nowhere/City")
+	})
+
+	start := time.Date(2024, 1, 31, 23, 59, 30, 500, time.UTC)
+	testcases := []struct {
+		spec   string
+		expect []time.Time
+	}{
+		{
+			spec: "*/10 * * * * *",
+			expect: []time.Time{
+				time.Date(2024, 1, 31, 23, 59, 40, 0, time.UTC),
+				time.Date(2024, 1, 31, 23, 59, 50, 0, time.UTC),
+				time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 30 9 * * MON-FRI",
+			expect: []time.Time{
+				time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, 2, 2, 9, 30, 0, 0, time.UTC),
+				time.Date(2024, 2, 5, 9, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 29 FEB *",
+			expect: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// either the 13th or a friday.
+			spec: "0 0 0 13 * 5",
+			expect: []time.Time{
+				time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 13, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@monthly",
+			expect: []time.Time{
+				time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 7 * * SUN",
+			expect: []time.Time{
+				time.Date(2024, 2, 4, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 11, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, c := range testcases {
+		s, err := ParseCron(c.spec)
+		assert.Nil(t, err)
+		assert.Equal(t, s.String(), c.spec)
+		next := start
+		for _, expect := range c.expect {
+			next = s.Next(next)
+			assert.Equal(t, next, expect, c.spec)
+		}
+	}
+}
+
+func TestCronSchedule_TimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip(err)
+	}
+	s, err := ParseCron("CRON_TZ=Asia/Shanghai 0 0 8 * * *")
+	assert.Nil(t, err)
+	next := s.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, next, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	assert.True(t, next.In(loc).Hour() == 8)
+}
@@ -0,0 +1,283 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-spring.dev/spring/metrics"
+)
+
+var (
+	ErrRejected         = errors.New("task rejected")
+	ErrExecutorShutdown = errors.New("executor shutdown")
+)
+
+// RejectionPolicy decides what happens to a task submitted to a full executor.
+type RejectionPolicy string
+
+const (
+	Abort         = RejectionPolicy("abort")          // Submit returns ErrRejected.
+	CallerRuns    = RejectionPolicy("caller-runs")    // the task runs in the goroutine calling Submit.
+	Discard       = RejectionPolicy("discard")        // the task is dropped silently.
+	DiscardOldest = RejectionPolicy("discard-oldest") // the oldest queued task is dropped to make room.
+)
+
+// ExecutorConfig is the configuration of an Executor, it's bound from the
+// properties `task.executors.<name>`.
+type ExecutorConfig struct {
+	Workers         int             `value:"${workers:=0}"`              // the number of workers, defaults to the number of CPUs.
+	QueueSize       int             `value:"${queue-size:=100}"`         // the capacity of the queue.
+	RejectionPolicy RejectionPolicy `value:"${rejection-policy:=abort}"` // abort, caller-runs, discard or discard-oldest.
+	DrainTimeout    time.Duration   `value:"${drain-timeout:=30s}"`      // the longest wait for the tasks when the application stops.
+}
+
+// ExecutorStats is a snapshot of the metrics of an Executor.
+type ExecutorStats struct {
+	QueueDepth int   // the number of queued tasks.
+	Active     int64 // the number of running tasks.
+	Completed  int64 // the number of completed tasks, the panicked ones excluded.
+	Panicked   int64 // the number of panicked tasks.
+	Rejected   int64 // the number of rejected or discarded tasks.
+}
+
+// ExecutorOption configures an Executor.
+type ExecutorOption func(e *Executor)
+
+// WithExecutorLogger sets the logger of the executor.
+func WithExecutorLogger(logger *slog.Logger) ExecutorOption {
+	return func(e *Executor) {
+		e.logger = logger
+	}
+}
+
+// WithExecutorMetrics reports the metrics of the executor to the registry, labeled by
+// the name of the executor.
+func WithExecutorMetrics(r *metrics.Registry) ExecutorOption {
+	return func(e *Executor) {
+		e.registry = r
+	}
+}
+
+// WithDecorator sets the function decorating the context of submitted tasks, for
+// example to carry values of the application.
+func WithDecorator(fn func(ctx context.Context) context.Context) ExecutorOption {
+	return func(e *Executor) {
+		e.decorate = fn
+	}
+}
+
+type submission struct {
+	ctx context.Context
+	fn  Func
+}
+
+// Executor runs submitted tasks on a fixed number of workers with a bounded queue.
+type Executor struct {
+	name     string
+	policy   RejectionPolicy
+	drain    time.Duration
+	logger   *slog.Logger
+	registry *metrics.Registry
+	decorate func(ctx context.Context) context.Context
+
+	queue chan submission
+	wg    sync.WaitGroup
+
+	mutex    sync.RWMutex
+	shutdown bool
+
+	active    atomic.Int64
+	completed atomic.Int64
+	panicked  atomic.Int64
+	rejected  atomic.Int64
+
+	queueGauge  *metrics.Gauge
+	activeGauge *metrics.Gauge
+}
+
+// NewExecutor returns an Executor whose workers are started immediately.
+func NewExecutor(name string, config ExecutorConfig, opts ...ExecutorOption) (*Executor, error) {
+
+	switch config.RejectionPolicy {
+	case "":
+		config.RejectionPolicy = Abort
+	case Abort, CallerRuns, Discard, DiscardOldest:
+	default:
+		return nil, fmt.Errorf("executor %q error: unknown rejection policy %q", name, config.RejectionPolicy)
+	}
+	if config.Workers < 0 || config.QueueSize < 0 {
+		return nil, fmt.Errorf("executor %q error: workers and queue size can't be negative", name)
+	}
+	if config.Workers == 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	e := &Executor{
+		name:     name,
+		policy:   config.RejectionPolicy,
+		drain:    config.DrainTimeout,
+		logger:   slog.Default(),
+		registry: metrics.NewRegistry(),
+		decorate: func(ctx context.Context) context.Context {
+			return ctx
+		},
+		queue: make(chan submission, config.QueueSize),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.queueGauge = e.registry.Gauge("task_executor_queue_depth",
+		"The number of queued tasks of the executor.", "executor").With(name)
+	e.activeGauge = e.registry.Gauge("task_executor_active",
+		"The number of running tasks of the executor.", "executor").With(name)
+
+	for i := 0; i < config.Workers; i++ {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			for s := range e.queue {
+				e.queueGauge.Set(float64(len(e.queue)))
+				e.execute(s)
+			}
+		}()
+	}
+	return e, nil
+}
+
+// Name returns the name of the executor.
+func (e *Executor) Name() string {
+	return e.name
+}
+
+// Submit queues a task, the task receives ctx decorated by the executor. When the
+// queue is full the rejection policy of the executor applies.
+func (e *Executor) Submit(ctx context.Context, fn Func) error {
+	s := submission{ctx: e.decorate(ctx), fn: fn}
+	callerRuns, err := e.offer(s)
+	if callerRuns {
+		e.execute(s)
+	}
+	return err
+}
+
+// offer queues the task, it returns whether the caller should run the task itself.
+func (e *Executor) offer(s submission) (callerRuns bool, err error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if e.shutdown {
+		return false, ErrExecutorShutdown
+	}
+
+	for {
+		select {
+		case e.queue <- s:
+			e.queueGauge.Set(float64(len(e.queue)))
+			return false, nil
+		default:
+		}
+
+		switch e.policy {
+		case CallerRuns:
+			return true, nil
+		case Discard:
+			e.rejected.Add(1)
+			return false, nil
+		case DiscardOldest:
+			select {
+			case <-e.queue:
+				e.rejected.Add(1)
+			default:
+			}
+			if cap(e.queue) > 0 {
+				continue // retry after making room.
+			}
+			e.rejected.Add(1)
+			return false, nil
+		default:
+			e.rejected.Add(1)
+			return false, ErrRejected
+		}
+	}
+}
+
+// execute runs the task once, a panic of the task doesn't affect the worker.
+func (e *Executor) execute(s submission) {
+	e.activeGauge.Set(float64(e.active.Add(1)))
+	defer func() {
+		e.activeGauge.Set(float64(e.active.Add(-1)))
+		if r := recover(); r != nil {
+			e.panicked.Add(1)
+			e.logger.Error(fmt.Sprint(r), slog.String("executor", e.name), slog.String("stack", string(debug.Stack())))
+			return
+		}
+		e.completed.Add(1)
+	}()
+	if err := s.fn(s.ctx); err != nil {
+		e.logger.Error("task execution failed", slog.String("executor", e.name), slog.Any("err", err))
+	}
+}
+
+// DrainTimeout returns the longest wait for the tasks when the application stops, a
+// non-positive value means no limit.
+func (e *Executor) DrainTimeout() time.Duration {
+	return e.drain
+}
+
+// Stats returns the metrics of the executor.
+func (e *Executor) Stats() ExecutorStats {
+	return ExecutorStats{
+		QueueDepth: len(e.queue),
+		Active:     e.active.Load(),
+		Completed:  e.completed.Load(),
+		Panicked:   e.panicked.Load(),
+		Rejected:   e.rejected.Load(),
+	}
+}
+
+// Shutdown stops accepting tasks and waits for the queued and running tasks to
+// complete, it returns the error of ctx when ctx is done first.
+func (e *Executor) Shutdown(ctx context.Context) error {
+	e.mutex.Lock()
+	if !e.shutdown {
+		e.shutdown = true
+		close(e.queue)
+	}
+	e.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
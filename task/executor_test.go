@@ -0,0 +1,176 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/spring/metrics"
+)
+
+type ctxKey struct{}
+
+func TestNewExecutor(t *testing.T) {
+	_, err := NewExecutor("io", ExecutorConfig{RejectionPolicy: "block"})
+	assert.Error(t, err, "executor \"io\" error: unknown rejection policy \"block\"")
+	_, err = NewExecutor("io", ExecutorConfig{Workers: -1})
+	assert.Error(t, err, "executor \"io\" error: workers and queue size can't be negative")
+}
+
+// newBlockedExecutor returns an executor with a single worker blocked until release
+// is closed, and a queue of one task.
+func newBlockedExecutor(t *testing.T, policy RejectionPolicy) (*Executor, chan struct{}) {
+	e, err := NewExecutor("test", ExecutorConfig{Workers: 1, QueueSize: 1, RejectionPolicy: policy})
+	assert.Nil(t, err)
+	started, release := make(chan struct{}), make(chan struct{})
+	err = e.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	assert.Nil(t, err)
+	<-started
+	return e, release
+}
+
+func TestExecutor_RejectionPolicy(t *testing.T) {
+
+	noop := func(ctx context.Context) error { return nil }
+
+	t.Run("abort", func(t *testing.T) {
+		e, release := newBlockedExecutor(t, Abort)
+		assert.Nil(t, e.Submit(context.Background(), noop))
+		err := e.Submit(context.Background(), noop)
+		assert.True(t, errors.Is(err, ErrRejected))
+		assert.Equal(t, e.Stats(), ExecutorStats{QueueDepth: 1, Active: 1, Rejected: 1})
+		close(release)
+		assert.Nil(t, e.Shutdown(context.Background()))
+		assert.Equal(t, e.Stats(), ExecutorStats{Completed: 2, Rejected: 1})
+	})
+
+	t.Run("caller-runs", func(t *testing.T) {
+		e, release := newBlockedExecutor(t, CallerRuns)
+		assert.Nil(t, e.Submit(context.Background(), noop))
+		var ran bool
+		err := e.Submit(context.Background(), func(ctx context.Context) error {
+			ran = true
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.True(t, ran)
+		close(release)
+		assert.Nil(t, e.Shutdown(context.Background()))
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		e, release := newBlockedExecutor(t, Discard)
+		assert.Nil(t, e.Submit(context.Background(), noop))
+		assert.Nil(t, e.Submit(context.Background(), noop))
+		assert.Equal(t, e.Stats().Rejected, int64(1))
+		close(release)
+		assert.Nil(t, e.Shutdown(context.Background()))
+	})
+
+	t.Run("discard-oldest", func(t *testing.T) {
+		e, release := newBlockedExecutor(t, DiscardOldest)
+		var mark atomic.Int32
+		for i := int32(1); i <= 3; i++ {
+			i := i
+			err := e.Submit(context.Background(), func(ctx context.Context) error {
+				mark.Store(i)
+				return nil
+			})
+			assert.Nil(t, err)
+		}
+		close(release)
+		assert.Nil(t, e.Shutdown(context.Background()))
+		assert.Equal(t, mark.Load(), int32(3))
+		assert.Equal(t, e.Stats().Rejected, int64(2))
+	})
+}
+
+func TestExecutor_Shutdown(t *testing.T) {
+
+	e, err := NewExecutor("test", ExecutorConfig{Workers: 2, QueueSize: 10}, WithDecorator(func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, ctxKey{}, "app")
+	}))
+	assert.Nil(t, err)
+
+	var count atomic.Int32
+	for i := 0; i < 10; i++ {
+		i := i
+		err = e.Submit(context.Background(), func(ctx context.Context) error {
+			if ctx.Value(ctxKey{}) == "app" {
+				count.Add(1)
+			}
+			if i == 5 {
+				panic("boom")
+			}
+			return nil
+		})
+		assert.Nil(t, err)
+	}
+
+	// the queued tasks are drained.
+	assert.Nil(t, e.Shutdown(context.Background()))
+	assert.Equal(t, count.Load(), int32(10))
+	assert.Equal(t, e.Stats().Completed, int64(9))
+	assert.Equal(t, e.Stats().Panicked, int64(1))
+	assert.Equal(t, e.DrainTimeout(), time.Duration(0))
+
+	err = e.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	assert.True(t, errors.Is(err, ErrExecutorShutdown))
+
+	t.Run("timeout", func(t *testing.T) {
+		e, release := newBlockedExecutor(t, Abort)
+		defer close(release)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.True(t, errors.Is(e.Shutdown(ctx), context.DeadlineExceeded))
+	})
+}
+
+func TestExecutor_Metrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	e, err := NewExecutor("io", ExecutorConfig{Workers: 1, QueueSize: 2}, WithExecutorMetrics(r))
+	assert.Nil(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	err = e.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	assert.Nil(t, err)
+	<-started
+	assert.Nil(t, e.Submit(context.Background(), func(ctx context.Context) error { return nil }))
+
+	queue := r.Gauge("task_executor_queue_depth", "", "executor").With("io")
+	active := r.Gauge("task_executor_active", "", "executor").With("io")
+	assert.Equal(t, queue.Value(), float64(1))
+	assert.Equal(t, active.Value(), float64(1))
+
+	close(release)
+	assert.Nil(t, e.Shutdown(context.Background()))
+	assert.Equal(t, queue.Value(), float64(0))
+	assert.Equal(t, active.Value(), float64(0))
+}
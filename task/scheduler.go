@@ -0,0 +1,293 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package task provides scheduled tasks with cron, fixed-rate and fixed-delay triggers.
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Func is the function executed by a task.
+type Func func(ctx context.Context) error
+
+// Configurer is implemented by beans which register scheduled tasks.
+type Configurer interface {
+	ConfigureTasks(s *Scheduler) error
+}
+
+// Option configures a Scheduler.
+type Option func(s *Scheduler)
+
+// WithClock sets the clock of the scheduler, it defaults to the system clock.
+func WithClock(clock Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithLogger sets the logger of the scheduler.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = logger
+	}
+}
+
+// WithResolver sets the function resolving `${key:=def}` placeholders in the
+// cron expressions and durations of tasks.
+func WithResolver(fn func(s string) (string, error)) Option {
+	return func(s *Scheduler) {
+		s.resolve = fn
+	}
+}
+
+// TaskOption configures a task.
+type TaskOption func(t *scheduledTask)
+
+// AllowOverlap runs the executions of a task in their own goroutine, so that an
+// execution can start before the previous one completes. It has no effect on
+// fixed-delay tasks.
+func AllowOverlap() TaskOption {
+	return func(t *scheduledTask) {
+		t.allowOverlap = true
+	}
+}
+
+// InitialDelay delays the first execution of a fixed-rate or fixed-delay task, the
+// delay is a duration string such as `10s` or a placeholder such as `${job.delay:=10s}`.
+func InitialDelay(delay string) TaskOption {
+	return func(t *scheduledTask) {
+		t.initialDelay = delay
+	}
+}
+
+type scheduledTask struct {
+	name         string
+	trigger      Trigger
+	fn           Func
+	allowOverlap bool
+	initialDelay string
+}
+
+// Scheduler runs tasks on their triggers.
+type Scheduler struct {
+	clock   Clock
+	logger  *slog.Logger
+	resolve func(s string) (string, error)
+
+	mutex   sync.Mutex
+	tasks   []*scheduledTask
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// NewScheduler returns a Scheduler.
+func NewScheduler(opts ...Option) *Scheduler {
+	s := &Scheduler{
+		clock:  SystemClock(),
+		logger: slog.Default(),
+		resolve: func(s string) (string, error) {
+			return s, nil
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Cron schedules a task on a cron expression, the expression can be a placeholder
+// such as `${job.cron:=0 0 * * * *}`, see ParseCron for its syntax.
+func (s *Scheduler) Cron(name string, expr string, fn Func, opts ...TaskOption) error {
+	expr, err := s.resolve(expr)
+	if err != nil {
+		return fmt.Errorf("task %q error: %w", name, err)
+	}
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return fmt.Errorf("task %q error: %w", name, err)
+	}
+	return s.Schedule(name, Cron(schedule), fn, opts...)
+}
+
+// FixedRate schedules a task running every interval, the interval is a duration string
+// such as `5s` or a placeholder such as `${job.interval:=5s}`.
+func (s *Scheduler) FixedRate(name string, interval string, fn Func, opts ...TaskOption) error {
+	t := newTask(name, fn, opts)
+	period, delay, err := s.durations(interval, t.initialDelay)
+	if err != nil {
+		return fmt.Errorf("task %q error: %w", name, err)
+	}
+	t.trigger = FixedRate(period, delay)
+	return s.add(t)
+}
+
+// FixedDelay schedules a task running the delay after the previous execution completed,
+// the delay is a duration string such as `5s` or a placeholder such as `${job.delay:=5s}`.
+func (s *Scheduler) FixedDelay(name string, delay string, fn Func, opts ...TaskOption) error {
+	t := newTask(name, fn, opts)
+	d, initial, err := s.durations(delay, t.initialDelay)
+	if err != nil {
+		return fmt.Errorf("task %q error: %w", name, err)
+	}
+	t.trigger = FixedDelay(d, initial)
+	t.allowOverlap = false
+	return s.add(t)
+}
+
+func (s *Scheduler) durations(period, initialDelay string) (time.Duration, time.Duration, error) {
+	p, err := s.duration(period)
+	if err != nil {
+		return 0, 0, err
+	}
+	if p <= 0 {
+		return 0, 0, fmt.Errorf("non-positive duration %q", period)
+	}
+	if initialDelay == "" {
+		return p, 0, nil
+	}
+	d, err := s.duration(initialDelay)
+	if err != nil {
+		return 0, 0, err
+	}
+	return p, d, nil
+}
+
+func (s *Scheduler) duration(str string) (time.Duration, error) {
+	str, err := s.resolve(str)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(str)
+}
+
+func newTask(name string, fn Func, opts []TaskOption) *scheduledTask {
+	t := &scheduledTask{name: name, fn: fn}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Schedule schedules a task on a trigger. Tasks scheduled after Start run immediately.
+func (s *Scheduler) Schedule(name string, trigger Trigger, fn Func, opts ...TaskOption) error {
+	t := newTask(name, fn, opts)
+	t.trigger = trigger
+	return s.add(t)
+}
+
+func (s *Scheduler) add(t *scheduledTask) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.stopped {
+		return errors.New("scheduler stopped")
+	}
+	for _, task := range s.tasks {
+		if task.name == t.name {
+			return fmt.Errorf("duplicate task %q", t.name)
+		}
+	}
+	s.tasks = append(s.tasks, t)
+	if s.ctx != nil {
+		s.start(t)
+	}
+	return nil
+}
+
+// Start runs the scheduled tasks until ctx is canceled or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.ctx != nil || s.stopped {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, t := range s.tasks {
+		s.start(t)
+	}
+}
+
+// Stop cancels the scheduled tasks and waits for the running executions to complete.
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) start(t *scheduledTask) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(s.ctx, t)
+	}()
+}
+
+// run executes the task on its trigger until ctx is canceled.
+func (s *Scheduler) run(ctx context.Context, t *scheduledTask) {
+	var last Execution
+	for {
+		now := s.clock.Now()
+		next := t.trigger.Next(now, last)
+		if next.IsZero() {
+			return
+		}
+
+		timer := s.clock.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		last.Scheduled = next
+		if t.allowOverlap {
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.execute(ctx, t)
+			}()
+			continue
+		}
+
+		s.execute(ctx, t)
+		last.Completed = s.clock.Now()
+	}
+}
+
+// execute runs the task once, a panic of the task doesn't affect others.
+func (s *Scheduler) execute(ctx context.Context, t *scheduledTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error(fmt.Sprint(r), slog.String("task", t.name), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+	if err := t.fn(ctx); err != nil {
+		s.logger.Error("task execution failed", slog.String("task", t.name), slog.Any("err", err))
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestScheduler_FixedRate(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(WithClock(clock))
+
+	ch := make(chan time.Time)
+	err := s.FixedRate("rate", "10s", func(ctx context.Context) error {
+		ch <- clock.Now()
+		return nil
+	}, InitialDelay("5s"))
+	assert.Nil(t, err)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, <-ch, epoch.Add(5*time.Second))
+
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, <-ch, epoch.Add(15*time.Second))
+}
+
+func TestScheduler_FixedDelay(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(WithClock(clock))
+
+	ch := make(chan time.Time, 1)
+	err := s.FixedDelay("delay", "10s", func(ctx context.Context) error {
+		ch <- clock.Now()
+		clock.Advance(3 * time.Second) // the execution lasts 3s.
+		return nil
+	})
+	assert.Nil(t, err)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Equal(t, <-ch, epoch)
+
+	clock.BlockUntil(1)
+	clock.Advance(9 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("should not execute before the delay")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Second)
+	assert.Equal(t, <-ch, epoch.Add(13*time.Second))
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	clock := NewManualClock(epoch.Add(5 * time.Second))
+	s := NewScheduler(WithClock(clock))
+
+	ch := make(chan time.Time)
+	err := s.Cron("cron", "*/10 * * * * *", func(ctx context.Context) error {
+		ch <- clock.Now()
+		return nil
+	})
+	assert.Nil(t, err)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, <-ch, epoch.Add(10*time.Second))
+
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, <-ch, epoch.Add(20*time.Second))
+}
+
+func TestScheduler_NoOverlap(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(WithClock(clock))
+
+	var running, maxRunning int32
+	started := make(chan time.Time)
+	release := make(chan struct{})
+	err := s.FixedRate("rate", "1s", func(ctx context.Context) error {
+		if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+		defer atomic.AddInt32(&running, -1)
+		started <- clock.Now()
+		<-release
+		return nil
+	})
+	assert.Nil(t, err)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	assert.Equal(t, <-started, epoch)
+
+	// the missed executions are skipped while the task is running.
+	clock.Advance(2500 * time.Millisecond)
+	release <- struct{}{}
+
+	clock.BlockUntil(1)
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, <-started, epoch.Add(3*time.Second))
+	release <- struct{}{}
+
+	assert.Equal(t, atomic.LoadInt32(&maxRunning), int32(1))
+}
+
+func TestScheduler_Panic(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(WithClock(clock))
+
+	var count int32
+	ch := make(chan struct{})
+	err := s.FixedRate("panic", "1s", func(ctx context.Context) error {
+		if atomic.AddInt32(&count, 1) == 1 {
+			panic("boom")
+		}
+		ch <- struct{}{}
+		return errors.New("failed")
+	})
+	assert.Nil(t, err)
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-ch
+	assert.Equal(t, atomic.LoadInt32(&count), int32(2))
+}
+
+func TestScheduler_Resolver(t *testing.T) {
+	s := NewScheduler(WithResolver(func(s string) (string, error) {
+		if strings.HasPrefix(s, "${") {
+			return "", errors.New("property not exist")
+		}
+		return s, nil
+	}))
+
+	err := s.FixedRate("rate", "${job.interval}", func(ctx context.Context) error { return nil })
+	assert.Error(t, err, "task \"rate\" error: property not exist")
+
+	err = s.Cron("cron", "0 0 * *", func(ctx context.Context) error { return nil })
+	assert.Error(t, err, "task \"cron\" error: parse cron \"0 0 \\* \\*\" error: expected 5 or 6 fields but got 4")
+
+	err = s.FixedDelay("delay", "0s", func(ctx context.Context) error { return nil })
+	assert.Error(t, err, "task \"delay\" error: non-positive duration \"0s\"")
+
+	err = s.FixedDelay("delay", "1s", func(ctx context.Context) error { return nil }, InitialDelay("soon"))
+	assert.Error(t, err, "task \"delay\" error: time: invalid duration \"soon\"")
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(WithClock(clock))
+
+	started := make(chan struct{})
+	err := s.FixedRate("rate", "1s", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Nil(t, err)
+
+	err = s.FixedRate("rate", "1s", func(ctx context.Context) error { return nil })
+	assert.Error(t, err, "duplicate task \"rate\"")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	<-started
+
+	// tasks scheduled after start run immediately.
+	ch := make(chan struct{})
+	err = s.FixedRate("late", "1s", func(ctx context.Context) error {
+		close(ch)
+		return nil
+	})
+	assert.Nil(t, err)
+	<-ch
+
+	cancel()
+	s.Stop()
+
+	err = s.FixedRate("stopped", "1s", func(ctx context.Context) error { return nil })
+	assert.Error(t, err, "scheduler stopped")
+}
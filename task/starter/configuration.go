@@ -0,0 +1,147 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/metrics"
+	"go-spring.dev/spring/task"
+)
+
+// Module is the module of the task auto-configuration, which an App created with
+// gs.WithoutAutoConfigs installs explicitly by `app.Install(starter.Module)`.
+var Module = gs.AutoConfigModule(autoConfig.Name())
+
+var autoConfig = gs.AutoConfiguration("task", configure)
+
+// configure registers the beans of the task auto-configuration.
+func configure(r gs.BeanDefinitionRegistry) error {
+	r.Configuration(new(schedulingConfiguration)).
+		On(cond.OnProperty("task.scheduling.enabled", cond.HavingValue("true"), cond.MatchIfMissing()))
+	return registerExecutors(r)
+}
+
+// registerExecutors registers an executor bean for each `task.executors.<name>`,
+// the bean is named by the name of the executor, and its metrics are reported to
+// the *metrics.Registry bean if any.
+func registerExecutors(r gs.BeanDefinitionRegistry) error {
+
+	var configs map[string]task.ExecutorConfig
+	if err := r.Properties().Bind(&configs, conf.Key("task.executors")); err != nil {
+		return err
+	}
+	if len(configs) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		name, config := name, configs[name]
+		r.Provide(func(ctx gs.Context, registry *metrics.Registry) (*task.Executor, error) {
+			opts := []task.ExecutorOption{
+				task.WithExecutorLogger(gs.GetLogger()),
+				task.WithDecorator(func(c context.Context) context.Context {
+					return gs.WithContext(c, ctx)
+				}),
+			}
+			if registry != nil {
+				opts = append(opts, task.WithExecutorMetrics(registry))
+			}
+			return task.NewExecutor(name, config, opts...)
+		}, "", "?").Name(name)
+	}
+	r.Object(new(executorLifecycle))
+	return nil
+}
+
+// executorLifecycle drains the executors when the application stops, each one waits
+// its tasks for `task.executors.<name>.drain-timeout` at most.
+type executorLifecycle struct {
+	Logger    *slog.Logger     `logger:""`
+	Executors []*task.Executor `autowire:"?"`
+}
+
+func (el *executorLifecycle) OnAppStart(ctx context.Context) {}
+
+func (el *executorLifecycle) OnAppStop(ctx context.Context) {
+	for _, e := range el.Executors {
+		el.drain(ctx, e)
+	}
+}
+
+func (el *executorLifecycle) drain(ctx context.Context, e *task.Executor) {
+	if timeout := e.DrainTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	el.Logger.Info("draining executor", slog.String("name", e.Name()), slog.Int("queue", e.Stats().QueueDepth))
+	if err := e.Shutdown(ctx); err != nil {
+		el.Logger.Error("executor drain failed", slog.String("name", e.Name()), slog.Any("err", err))
+		return
+	}
+	stats := e.Stats()
+	el.Logger.Info("executor drained", slog.String("name", e.Name()),
+		slog.Int64("completed", stats.Completed), slog.Int64("panicked", stats.Panicked))
+}
+
+type schedulingConfiguration struct {
+	Logger      *slog.Logger      `logger:""`
+	Scheduler   *task.Scheduler   `autowire:""`
+	Configurers []task.Configurer `autowire:"?"`
+}
+
+func (sc *schedulingConfiguration) OnInit(ctx context.Context) error {
+	for _, c := range sc.Configurers {
+		if err := c.ConfigureTasks(sc.Scheduler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *schedulingConfiguration) OnAppStart(ctx context.Context) {
+	sc.Logger.Info("starting task scheduler")
+	sc.Scheduler.Start(ctx)
+}
+
+func (sc *schedulingConfiguration) OnAppStop(ctx context.Context) {
+	sc.Logger.Info("stopping task scheduler")
+	sc.Scheduler.Stop()
+}
+
+func (sc *schedulingConfiguration) NewScheduler() *gs.BeanDefinition {
+	return gs.NewBean(
+		func(ctx gs.Context, clock task.Clock) *task.Scheduler {
+			opts := []task.Option{task.WithResolver(ctx.Resolve), task.WithLogger(gs.GetLogger())}
+			if clock != nil {
+				opts = append(opts, task.WithClock(clock))
+			}
+			return task.NewScheduler(opts...)
+		}, "", "?").Primary()
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/spring/metrics"
+	"go-spring.dev/spring/task"
+)
+
+type service struct {
+	Executor *task.Executor `autowire:"io"`
+}
+
+func TestExecutors(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("task.executors.io.workers", 1))
+	assert.Nil(t, p.Set("task.executors.io.queue-size", 10))
+	assert.Nil(t, p.Set("task.executors.io.drain-timeout", "50ms"))
+
+	s := new(service)
+	app := gs.NewApp(gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+	app.Object(s)
+	assert.Nil(t, app.Start(context.Background()))
+	assert.Equal(t, s.Executor.Name(), "io")
+	assert.Equal(t, s.Executor.DrainTimeout(), 50*time.Millisecond)
+
+	done := make(chan bool, 1)
+	err := s.Executor.Submit(context.Background(), func(ctx context.Context) error {
+		done <- gs.FromContext(ctx) != nil
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, <-done)
+
+	err = s.Executor.Submit(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	})
+	assert.Nil(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	err = s.Executor.Submit(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	assert.Nil(t, err)
+	<-started
+	assert.Nil(t, s.Executor.Submit(context.Background(), func(ctx context.Context) error { return nil }))
+
+	queue := metrics.Default.Gauge("task_executor_queue_depth", "", "executor").With("io")
+	active := metrics.Default.Gauge("task_executor_active", "", "executor").With("io")
+	assert.Equal(t, queue.Value(), float64(1))
+	assert.Equal(t, active.Value(), float64(1))
+
+	// the blocked task is waited for the drain timeout only.
+	start := time.Now()
+	assert.Nil(t, app.Stop(context.Background()))
+	assert.True(t, time.Since(start) < time.Second)
+
+	stats := s.Executor.Stats()
+	assert.Equal(t, stats.Completed, int64(1))
+	assert.Equal(t, stats.Panicked, int64(1))
+	assert.Equal(t, stats.QueueDepth, 1)
+
+	err = s.Executor.Submit(context.Background(), func(ctx context.Context) error { return nil })
+	assert.True(t, errors.Is(err, task.ErrExecutorShutdown))
+}
+
+func TestExecutors_Invalid(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("task.executors.io.rejection-policy", "block"))
+
+	app := gs.NewApp(gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+	err := app.Start(context.Background())
+	assert.Error(t, err, `executor "io" error: unknown rejection policy "block"`)
+}
+
+func TestModule(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("task.executors.io.workers", 1))
+
+	for _, opts := range [][]gs.AppOption{nil, {gs.WithoutAutoConfigs()}} {
+		opts = append(opts, gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+		s := new(service)
+		app := gs.NewApp(opts...)
+		app.Install(Module)
+		app.Object(s)
+		assert.Nil(t, app.Start(context.Background()))
+		assert.Equal(t, s.Executor.Name(), "io")
+		assert.Nil(t, app.Stop(context.Background()))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"time"
+)
+
+// Execution records the last execution of a task, the times are zero before the first execution.
+type Execution struct {
+	Scheduled time.Time // the time the execution was scheduled at.
+	Completed time.Time // the time the execution completed at.
+}
+
+// Trigger computes the next execution time of a task.
+type Trigger interface {
+	// Next returns the next execution time after now, or zero time to stop the task.
+	Next(now time.Time, last Execution) time.Time
+}
+
+// Cron returns a Trigger firing on the times matched by the cron schedule.
+func Cron(s *CronSchedule) Trigger {
+	return cronTrigger{s}
+}
+
+type cronTrigger struct {
+	s *CronSchedule
+}
+
+func (t cronTrigger) Next(now time.Time, last Execution) time.Time {
+	return t.s.Next(now)
+}
+
+// FixedRate returns a Trigger firing every period, the first execution starts after the
+// initial delay. When an execution lasts longer than the period, the missed executions are
+// skipped so that executions never overlap.
+func FixedRate(period, initialDelay time.Duration) Trigger {
+	return fixedRate{period: period, initialDelay: initialDelay}
+}
+
+type fixedRate struct {
+	period       time.Duration
+	initialDelay time.Duration
+}
+
+func (t fixedRate) Next(now time.Time, last Execution) time.Time {
+	if last.Scheduled.IsZero() {
+		return now.Add(t.initialDelay)
+	}
+	next := last.Scheduled.Add(t.period)
+	for next.Before(now) {
+		next = next.Add(t.period)
+	}
+	return next
+}
+
+// FixedDelay returns a Trigger firing the delay after the previous execution completed,
+// the first execution starts after the initial delay.
+func FixedDelay(delay, initialDelay time.Duration) Trigger {
+	return fixedDelay{delay: delay, initialDelay: initialDelay}
+}
+
+type fixedDelay struct {
+	delay        time.Duration
+	initialDelay time.Duration
+}
+
+func (t fixedDelay) Next(now time.Time, last Execution) time.Time {
+	if last.Completed.IsZero() {
+		return now.Add(t.initialDelay)
+	}
+	return last.Completed.Add(t.delay)
+}
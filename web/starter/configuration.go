@@ -17,59 +17,57 @@
 package starter
 
 import (
-	"context"
-	"errors"
-	"fmt"
-	"log/slog"
-	"net/http"
-
 	"go-spring.dev/spring/conf"
 	"go-spring.dev/spring/gs"
 	"go-spring.dev/spring/gs/cond"
+	"go-spring.dev/spring/metrics"
 	"go-spring.dev/web"
 	"go-spring.dev/web/binding"
 )
 
-func init() {
-	gs.Configuration(new(serverConfiguration)).
-		On(cond.OnProperty("http.addr"))
+// Module is the module of the web auto-configuration, which an App created with
+// gs.WithoutAutoConfigs installs explicitly by `app.Install(starter.Module)`.
+var Module = gs.AutoConfigModule(autoConfig.Name())
 
-	binding.RegisterValidator(conf.ValidateStruct)
-}
+var autoConfig = gs.AutoConfiguration("web", configure).After("health", "metrics")
 
-type serverConfiguration struct {
-	Logger *slog.Logger `logger:""`
-	Server *web.Server  `autowire:""`
+func init() {
+	binding.RegisterValidator(conf.ValidateStruct)
 }
 
-func (sc *serverConfiguration) OnAppStart(ctx context.Context) {
-	sc.Logger.Info("starting http server", slog.String("addr", sc.Server.Addr()))
-
-	go func() {
-		if err := sc.Server.Run(); nil != err && !errors.Is(err, http.ErrServerClosed) {
-			panic(fmt.Errorf("failed to start http server `%s`: %w", sc.Server.Addr(), err))
-		}
-	}()
+// configure registers the beans of the web auto-configuration.
+func configure(r gs.BeanDefinitionRegistry) error {
+	r.Configuration(new(serverConfiguration)).
+		On(cond.OnProperty("http.addr"))
+	r.Object(new(healthEndpoint)).
+		On(cond.OnProperty("http.addr")).
+		On(cond.OnProperty("http.health.enabled", cond.HavingValue("true"), cond.MatchIfMissing())).
+		On(cond.OnBean((*gs.HealthRegistry)(nil)))
+	r.Object(new(httpMetrics)).
+		On(cond.OnProperty("http.metrics.enabled", cond.HavingValue("true"), cond.MatchIfMissing())).
+		On(cond.OnBean((*metrics.Registry)(nil)))
+	r.Object(new(management)).
+		On(cond.OnProperty("management.enabled", cond.HavingValue("true")))
+	return registerServers(r)
 }
 
-func (sc *serverConfiguration) OnAppStop(ctx context.Context) {
-	sc.Logger.Info("stopping http server", slog.String("addr", sc.Server.Addr()))
-
-	if err := sc.Server.Shutdown(ctx); nil != err {
-		sc.Logger.Error("http server shutdown failed", slog.String("addr", sc.Server.Addr()), slog.Any("err", err))
-	} else {
-		sc.Logger.Info("http server shutdown successfully", slog.String("addr", sc.Server.Addr()))
-	}
-}
+// serverConfiguration provides the router of the default http server configured by the
+// properties `http`, the server itself is managed with the servers of `http.servers`.
+type serverConfiguration struct{}
 
 func (sc *serverConfiguration) NewRouter() *gs.BeanDefinition {
-	return gs.NewBean(web.NewRouter).Primary()
+	return gs.NewBean(web.NewRouter).Alias(defaultServer).Primary()
 }
 
+// NewServer provides the *web.Server for compatibility, the router is served by the
+// http server of the configuration which supports the properties `http.tls`.
 func (sc *serverConfiguration) NewServer() *gs.BeanDefinition {
-	return gs.NewBean(
-		func(router web.Router, options web.Options) *web.Server {
-			options.Router = router
-			return web.NewServer(options)
-		}, "", "${http}").Primary()
+	return gs.NewBean(newServer, "", "${http}").Primary()
+}
+
+// newServer is a function rather than a closure of NewServer, which would be taken as a
+// method of the router and fail when there are routers of several servers.
+func newServer(router web.Router, options web.Options) *web.Server {
+	options.Router = router
+	return web.NewServer(options)
 }
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/web"
+)
+
+// healthEndpoint serves the aggregated health at `http.health.path`, and the health
+// of a group such as liveness or readiness at `<path>/<group>`.
+type healthEndpoint struct {
+	Path     string             `value:"${http.health.path:=/health}"`
+	Router   web.Router         `autowire:""`
+	Registry *gs.HealthRegistry `autowire:""`
+}
+
+func (he *healthEndpoint) OnInit(ctx context.Context) error {
+	path := "/" + strings.Trim(he.Path, "/")
+	he.Router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, he.Registry.Health(r.Context()))
+	})
+	for _, group := range he.Registry.Groups() {
+		group := group
+		he.Router.HandleFunc(path+"/"+group, func(w http.ResponseWriter, r *http.Request) {
+			report, _ := he.Registry.Group(r.Context(), group)
+			writeHealth(w, report)
+		})
+	}
+	return nil
+}
+
+// writeHealth writes the report as json, the status code is 503 when the state is DOWN.
+func writeHealth(w http.ResponseWriter, report gs.HealthReport) {
+	code := http.StatusOK
+	if report.State == gs.HealthDown {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, report)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/web"
+)
+
+func TestHealthEndpoint(t *testing.T) {
+	state := gs.HealthUp
+	registry := gs.NewHealthRegistry(0)
+	registry.Register("db", gs.HealthIndicatorFunc(func(ctx context.Context) gs.HealthStatus {
+		return gs.HealthStatus{State: state}
+	}), "readiness")
+	registry.Register("disk", gs.HealthIndicatorFunc(func(ctx context.Context) gs.HealthStatus {
+		return gs.HealthStatus{State: gs.HealthUp}
+	}), "liveness")
+
+	router := web.NewRouter()
+	he := &healthEndpoint{Path: "/status/", Router: router, Registry: registry}
+	assert.Nil(t, he.OnInit(context.Background()))
+
+	var report gs.HealthReport
+	code := serve(t, router.ServeHTTP, http.MethodGet, "/status", "", &report)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, report.State, gs.HealthUp)
+	assert.Equal(t, len(report.Components), 2)
+
+	state = gs.HealthDown
+	report = gs.HealthReport{}
+	code = serve(t, router.ServeHTTP, http.MethodGet, "/status", "", &report)
+	assert.Equal(t, code, http.StatusServiceUnavailable)
+	assert.Equal(t, report.State, gs.HealthDown)
+	assert.Equal(t, report.Components["db"].State, gs.HealthDown)
+
+	report = gs.HealthReport{}
+	code = serve(t, router.ServeHTTP, http.MethodGet, "/status/readiness", "", &report)
+	assert.Equal(t, code, http.StatusServiceUnavailable)
+	assert.Equal(t, len(report.Components), 1)
+
+	report = gs.HealthReport{}
+	code = serve(t, router.ServeHTTP, http.MethodGet, "/status/liveness", "", &report)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, report.Components["disk"].State, gs.HealthUp)
+
+	code = serve(t, router.ServeHTTP, http.MethodGet, "/status/startup", "", nil)
+	assert.Equal(t, code, http.StatusNotFound)
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package listener listens on tcp addresses, unix domain sockets and the sockets passed
+// by systemd socket activation.
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// UnixPrefix is the prefix of the addresses of unix domain sockets, such as `unix:/run/app.sock`.
+	UnixPrefix = "unix:"
+
+	// SystemdPrefix is the prefix of the addresses selecting a socket passed by systemd
+	// socket activation by its name in LISTEN_FDNAMES, such as `systemd:http`.
+	SystemdPrefix = "systemd:"
+
+	// listenFdsStart is the first file descriptor passed by socket activation.
+	listenFdsStart = 3
+)
+
+// inheritedListener is a listener passed by socket activation.
+type inheritedListener struct {
+	name string
+	l    net.Listener
+}
+
+var (
+	inheritOnce sync.Once
+	inheritMu   sync.Mutex
+	inherited   []*inheritedListener
+	inheritErr  error
+)
+
+// Listen listens on the address, which is a tcp address, `unix:<path>` or `systemd:<name>`.
+// A socket passed by socket activation with the same address is used before listening on a
+// new one, so the socket outlives the restarts of the process. The file mode of a new unix
+// domain socket is set to mode.
+func Listen(addr string, mode fs.FileMode) (net.Listener, error) {
+
+	inheritOnce.Do(func() {
+		inherited, inheritErr = inherit(listenFdsStart, os.Getpid(), os.Getenv)
+		for _, key := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			_ = os.Unsetenv(key)
+		}
+	})
+	if inheritErr != nil {
+		return nil, inheritErr
+	}
+
+	if name, ok := strings.CutPrefix(addr, SystemdPrefix); ok {
+		if l := claim(func(il *inheritedListener) bool { return il.name == name }); l != nil {
+			return l, nil
+		}
+		return nil, fmt.Errorf("no socket named %q passed by socket activation", name)
+	}
+
+	if path, ok := strings.CutPrefix(addr, UnixPrefix); ok {
+		if l := claim(func(il *inheritedListener) bool { return sameAddr("unix", path, il.l.Addr()) }); l != nil {
+			return l, nil
+		}
+		return listenUnix(path, mode)
+	}
+
+	if l := claim(func(il *inheritedListener) bool { return sameAddr("tcp", addr, il.l.Addr()) }); l != nil {
+		return l, nil
+	}
+	return net.Listen("tcp", addr)
+}
+
+// listenUnix listens on a unix domain socket, a stale socket file left by a crashed process is removed.
+func listenUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&fs.ModeSocket != 0 {
+		if c, err := net.Dial("unix", path); err == nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("unix socket %q is in use", path)
+		}
+		if err = os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.Chmod(path, mode); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// claim removes and returns the first inherited listener accepted by fn.
+func claim(fn func(il *inheritedListener) bool) net.Listener {
+	inheritMu.Lock()
+	defer inheritMu.Unlock()
+	for i, il := range inherited {
+		if fn(il) {
+			inherited = append(inherited[:i], inherited[i+1:]...)
+			return il.l
+		}
+	}
+	return nil
+}
+
+// inherit returns the listeners passed by socket activation, which are described by the
+// environment variables LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES.
+func inherit(start int, pid int, getenv func(string) string) ([]*inheritedListener, error) {
+
+	if s := getenv("LISTEN_PID"); s == "" || s != strconv.Itoa(pid) {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(getenv("LISTEN_FDS"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", getenv("LISTEN_FDS"))
+	}
+
+	var names []string
+	if s := getenv("LISTEN_FDNAMES"); s != "" {
+		names = strings.Split(s, ":")
+	}
+
+	var ret []*inheritedListener
+	for i := 0; i < n; i++ {
+		name := "unknown"
+		if i < len(names) {
+			name = names[i]
+		}
+		f := os.NewFile(uintptr(start+i), name)
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("socket %q passed by socket activation error: %w", name, err)
+		}
+		ret = append(ret, &inheritedListener{name: name, l: l})
+	}
+	return ret, nil
+}
+
+// sameAddr returns whether the address a of the network is the address of the listener.
+func sameAddr(network, a string, addr net.Addr) bool {
+	if addr.Network() != network {
+		return false
+	}
+	if network == "unix" {
+		return a == addr.String()
+	}
+	ta, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	host, port, err := net.SplitHostPort(a)
+	if err != nil || port != strconv.Itoa(ta.Port) {
+		return false
+	}
+	if host == "" {
+		return ta.IP.IsUnspecified()
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.Equal(ta.IP) || ip.IsUnspecified() && ta.IP.IsUnspecified())
+}
+
+// ParseMode parses the file mode of unix domain sockets in octal, such as `0660`.
+func ParseMode(s string) (fs.FileMode, error) {
+	m, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || m&^uint64(fs.ModePerm) != 0 {
+		return 0, errors.New("invalid unix socket mode " + strconv.Quote(s))
+	}
+	return fs.FileMode(m), nil
+}
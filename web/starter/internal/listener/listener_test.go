@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listener
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	l, err := Listen(UnixPrefix+path, 0600)
+	assert.Nil(t, err)
+	fi, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.Equal(t, fi.Mode().Perm(), os.FileMode(0600))
+
+	_, err = Listen(UnixPrefix+path, 0600)
+	assert.Error(t, err, "is in use")
+	assert.Nil(t, l.Close())
+
+	// a stale socket file is removed.
+	ul, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	assert.Nil(t, err)
+	ul.SetUnlinkOnClose(false)
+	assert.Nil(t, ul.Close())
+	l, err = Listen(UnixPrefix+path, 0660)
+	assert.Nil(t, err)
+	assert.Nil(t, l.Close())
+}
+
+func TestInherit(t *testing.T) {
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	assert.Nil(t, err) // f is owned by the inherited listener.
+
+	env := map[string]string{
+		"LISTEN_PID":     "100",
+		"LISTEN_FDS":     "1",
+		"LISTEN_FDNAMES": "http",
+	}
+	ls, err := inherit(int(f.Fd()), 101, func(key string) string { return env[key] })
+	assert.Nil(t, err)
+	assert.Nil(t, ls)
+
+	ls, err = inherit(int(f.Fd()), 100, func(key string) string { return env[key] })
+	assert.Nil(t, err)
+	assert.Equal(t, len(ls), 1)
+	assert.Equal(t, ls[0].name, "http")
+	assert.Equal(t, ls[0].l.Addr().String(), l.Addr().String())
+	assert.Nil(t, ls[0].l.Close())
+
+	env["LISTEN_FDS"] = "x"
+	_, err = inherit(int(f.Fd()), 100, func(key string) string { return env[key] })
+	assert.Error(t, err, "invalid LISTEN_FDS")
+}
+
+func TestSameAddr(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv6unspecified, Port: 8080}
+	assert.True(t, sameAddr("tcp", ":8080", addr))
+	assert.True(t, sameAddr("tcp", "0.0.0.0:8080", addr))
+	assert.False(t, sameAddr("tcp", "127.0.0.1:8080", addr))
+	assert.False(t, sameAddr("tcp", ":8081", addr))
+	assert.False(t, sameAddr("unix", ":8080", addr))
+	addr = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}
+	assert.True(t, sameAddr("tcp", "127.0.0.1:8080", addr))
+	assert.True(t, sameAddr("unix", "/run/app.sock", &net.UnixAddr{Name: "/run/app.sock", Net: "unix"}))
+}
+
+func TestParseMode(t *testing.T) {
+	for s, want := range map[string]os.FileMode{"0660": 0660, "600": 0600, "0777": 0777} {
+		m, err := ParseMode(s)
+		assert.Nil(t, err)
+		assert.Equal(t, m, want, s)
+	}
+	for _, s := range []string{"", "0888", "1777", "rw"} {
+		_, err := ParseMode(s)
+		assert.Error(t, err, "invalid unix socket mode "+strconv.Quote(s))
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package pathmatch matches request paths by include and exclude patterns.
+package pathmatch
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Matcher matches request paths by include and exclude patterns, a pattern has the
+// syntax of path.Match, and a trailing `/**` matches the path and all paths below it.
+type Matcher struct {
+	include []string
+	exclude []string
+}
+
+// New returns a Matcher, all paths are included when there is no include pattern.
+func New(include, exclude []string) (*Matcher, error) {
+	for _, patterns := range [][]string{include, exclude} {
+		for _, pattern := range patterns {
+			if !strings.HasPrefix(pattern, "/") {
+				return nil, fmt.Errorf("path pattern %q must start with /", pattern)
+			}
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), "/"); err != nil {
+				return nil, fmt.Errorf("path pattern %q error: %w", pattern, err)
+			}
+		}
+	}
+	return &Matcher{include: include, exclude: exclude}, nil
+}
+
+// Match returns whether the path is included and not excluded.
+func (m *Matcher) Match(p string) bool {
+	if len(m.include) > 0 && !matchAny(m.include, p) {
+		return false
+	}
+	return !matchAny(m.exclude, p)
+}
+
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if match(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern, p string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if ok, _ = path.Match(prefix, p); ok {
+			return true
+		}
+		// matches the leading segments of the path with the prefix.
+		n := strings.Count(prefix, "/")
+		segments := strings.SplitAfterN(p, "/", n+2)
+		if len(segments) <= n+1 {
+			return false
+		}
+		head := strings.TrimSuffix(strings.Join(segments[:n+1], ""), "/")
+		ok, _ = path.Match(prefix, head)
+		return ok
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
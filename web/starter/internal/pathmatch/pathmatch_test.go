@@ -0,0 +1,63 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pathmatch
+
+import (
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New([]string{"api/**"}, nil)
+	assert.Error(t, err, `path pattern "api/\*\*" must start with /`)
+	_, err = New(nil, []string{"/api/["})
+	assert.Error(t, err, `path pattern "/api/\[" error: syntax error in pattern`)
+}
+
+func TestMatcher_Match(t *testing.T) {
+
+	m, err := New(nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, m.Match("/"))
+	assert.True(t, m.Match("/api/users"))
+
+	m, err = New([]string{"/api/**", "/health"}, []string{"/api/internal/**", "/api/*/debug"})
+	assert.Nil(t, err)
+	for p, want := range map[string]bool{
+		"/":                 false,
+		"/health":           true,
+		"/health/liveness":  false,
+		"/api":              true,
+		"/api/":             true,
+		"/api/users":        true,
+		"/api/users/1":      true,
+		"/apis":             false,
+		"/api/internal":     false,
+		"/api/internal/gc":  false,
+		"/api/users/debug":  false,
+		"/api/users/debugx": true,
+	} {
+		assert.Equal(t, m.Match(p), want, p)
+	}
+
+	m, err = New([]string{"/v*/users/**"}, nil)
+	assert.Nil(t, err)
+	assert.True(t, m.Match("/v1/users"))
+	assert.True(t, m.Match("/v2/users/1/orders"))
+	assert.False(t, m.Match("/v1/orders"))
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package tlsconfig builds the tls.Config of http servers from properties, and reloads
+// the certificates when their files change.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options is the tls configuration bound from the properties `http.tls`.
+type Options struct {
+	CertFile       string        `value:"${cert-file:=}"`          // the certificate, possibly followed by intermediates.
+	KeyFile        string        `value:"${key-file:=}"`           // the private key of the certificate.
+	ClientCAFile   string        `value:"${client-ca-file:=}"`     // the CAs verifying client certificates.
+	ClientAuth     string        `value:"${client-auth:=}"`        // none, request, require, verify-if-given or require-and-verify.
+	MinVersion     string        `value:"${min-version:=1.2}"`     // 1.0, 1.1, 1.2 or 1.3.
+	CipherSuites   []string      `value:"${cipher-suites:=}"`      // the names of cipher suites, such as TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+	ReloadInterval time.Duration `value:"${reload-interval:=10s}"` // the interval checking the files, non-positive disables reloading.
+}
+
+// Enabled returns whether tls is configured.
+func (o Options) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != ""
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify-if-given":    tls.VerifyClientCertIfGiven,
+	"require-and-verify": tls.RequireAndVerifyClientCert,
+}
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Config holds the tls.Config built from the files, and rebuilds it when they change.
+type Config struct {
+	options Options
+	base    *tls.Config
+
+	mutex   sync.Mutex
+	modTime map[string]time.Time
+	current atomic.Pointer[tls.Config]
+}
+
+// New validates the options and loads the files.
+func New(o Options) (*Config, error) {
+
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, errors.New("tls requires both cert-file and key-file")
+	}
+
+	base := &tls.Config{}
+
+	if o.MinVersion != "" {
+		v, ok := versions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown tls min-version %q", o.MinVersion)
+		}
+		base.MinVersion = v
+	}
+
+	clientAuth := o.ClientAuth
+	if clientAuth == "" {
+		clientAuth = "none"
+		if o.ClientCAFile != "" {
+			clientAuth = "require-and-verify"
+		}
+	}
+	authType, ok := clientAuthTypes[clientAuth]
+	if !ok {
+		return nil, fmt.Errorf("unknown tls client-auth %q", o.ClientAuth)
+	}
+	if authType >= tls.VerifyClientCertIfGiven && o.ClientCAFile == "" {
+		return nil, fmt.Errorf("tls client-auth %q requires client-ca-file", clientAuth)
+	}
+	base.ClientAuth = authType
+
+	if len(o.CipherSuites) > 0 {
+		ids, err := cipherSuites(o.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		base.CipherSuites = ids
+	}
+
+	c := &Config{options: o, base: base, modTime: make(map[string]time.Time)}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func cipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, s := range tls.CipherSuites() {
+		known[s.Name] = s.ID
+	}
+	for _, s := range tls.InsecureCipherSuites() {
+		known[s.Name] = s.ID
+	}
+	var ids []uint16
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tls cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// TLSConfig returns the tls.Config of servers, it always serves the latest loaded files.
+func (c *Config) TLSConfig() *tls.Config {
+	cfg := c.base.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return c.current.Load(), nil
+	}
+	// http.Server requires a certificate source, GetConfigForClient takes precedence.
+	cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return &c.current.Load().Certificates[0], nil
+	}
+	return cfg
+}
+
+// Reload loads the files again when any of them changed, it returns whether the files
+// were loaded. The current configuration is kept when loading fails.
+func (c *Config) Reload() (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	files := []string{c.options.CertFile, c.options.KeyFile}
+	if c.options.ClientCAFile != "" {
+		files = append(files, c.options.ClientCAFile)
+	}
+
+	changed := false
+	modTime := make(map[string]time.Time)
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTime[file] = info.ModTime()
+		if !info.ModTime().Equal(c.modTime[file]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.options.CertFile, c.options.KeyFile)
+	if err != nil {
+		return false, err
+	}
+
+	cfg := c.base.Clone()
+	cfg.Certificates = []tls.Certificate{cert}
+
+	if c.options.ClientCAFile != "" {
+		b, err := os.ReadFile(c.options.ClientCAFile)
+		if err != nil {
+			return false, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return false, fmt.Errorf("no certificate found in %s", c.options.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+	}
+
+	c.current.Store(cfg)
+	c.modTime = modTime
+	return true, nil
+}
+
+// Watch reloads the files every reload interval until ctx is done, onReload receives
+// the result of each reload which loaded the files or failed.
+func (c *Config) Watch(ctx context.Context, onReload func(err error)) {
+	if c.options.ReloadInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.options.ReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ok, err := c.Reload(); ok || err != nil {
+				onReload(err)
+			}
+		}
+	}
+}
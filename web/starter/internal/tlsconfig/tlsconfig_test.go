@@ -0,0 +1,192 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+type keyPair struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newKeyPair returns a certificate for 127.0.0.1 signed by parent, or self-signed when parent is nil.
+func newKeyPair(t *testing.T, cn string, parent *keyPair) *keyPair {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return &keyPair{cert: cert, key: key}
+}
+
+func (p *keyPair) write(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	b, err := x509.MarshalECPrivateKey(p.key)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: p.cert.Raw}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}), 0600))
+	assert.Nil(t, os.Chtimes(certFile, modTime, modTime))
+	assert.Nil(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func (p *keyPair) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{p.cert.Raw}, PrivateKey: p.key}
+}
+
+func TestNew(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	newKeyPair(t, "server", nil).write(t, certFile, keyFile, time.Now())
+
+	_, err := New(Options{CertFile: certFile})
+	assert.Error(t, err, "tls requires both cert-file and key-file")
+	_, err = New(Options{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.4"})
+	assert.Error(t, err, "unknown tls min-version \"1.4\"")
+	_, err = New(Options{CertFile: certFile, KeyFile: keyFile, ClientAuth: "always"})
+	assert.Error(t, err, "unknown tls client-auth \"always\"")
+	_, err = New(Options{CertFile: certFile, KeyFile: keyFile, ClientAuth: "require-and-verify"})
+	assert.Error(t, err, "tls client-auth \"require-and-verify\" requires client-ca-file")
+	_, err = New(Options{CertFile: certFile, KeyFile: keyFile, CipherSuites: []string{"TLS_NONE"}})
+	assert.Error(t, err, "unknown tls cipher suite \"TLS_NONE\"")
+	_, err = New(Options{CertFile: certFile, KeyFile: filepath.Join(dir, "none.pem")})
+	assert.Error(t, err, "no such file or directory")
+
+	c, err := New(Options{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		MinVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+	})
+	assert.Nil(t, err)
+	cfg := c.current.Load()
+	assert.Equal(t, cfg.MinVersion, uint16(tls.VersionTLS12))
+	assert.Equal(t, cfg.ClientAuth, tls.NoClientCert)
+	assert.Equal(t, cfg.CipherSuites, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256})
+}
+
+func TestConfig_MutualTLSAndReload(t *testing.T) {
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+
+	ca := newKeyPair(t, "ca", nil)
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), 0600))
+
+	first := newKeyPair(t, "first", ca)
+	first.write(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	c, err := New(Options{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	assert.Nil(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = c.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := newKeyPair(t, "client", ca)
+
+	// serverName returns the common name of the server certificate.
+	serverName := func(certs ...tls.Certificate) (string, error) {
+		conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), &tls.Config{RootCAs: roots, Certificates: certs})
+		if err != nil {
+			return "", err
+		}
+		defer conn.Close()
+		if err = conn.Handshake(); err != nil {
+			return "", err
+		}
+		// the client certificate is verified after the handshake in tls 1.3.
+		if _, err = conn.Write([]byte("GET / HTTP/1.0\r\n\r\n")); err != nil {
+			return "", err
+		}
+		b := make([]byte, 1024)
+		if _, err = conn.Read(b); err != nil {
+			return "", err
+		}
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+	}
+
+	_, err = serverName()
+	assert.Error(t, err, "certificate required")
+
+	name, err := serverName(client.tlsCertificate())
+	assert.Nil(t, err)
+	assert.Equal(t, name, "first")
+
+	ok, err := c.Reload()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	newKeyPair(t, "second", ca).write(t, certFile, keyFile, time.Now())
+	ok, err = c.Reload()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	name, err = serverName(client.tlsCertificate())
+	assert.Nil(t, err)
+	assert.Equal(t, name, "second")
+
+	// a broken key is ignored and the current certificate is kept.
+	assert.Nil(t, os.WriteFile(keyFile, []byte("broken"), 0600))
+	assert.Nil(t, os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	_, err = c.Reload()
+	assert.NotNil(t, err)
+
+	name, err = serverName(client.tlsCertificate())
+	assert.Nil(t, err)
+	assert.Equal(t, name, "second")
+}
@@ -0,0 +1,418 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/metrics"
+	"go-spring.dev/spring/web/starter/internal/listener"
+	"go-spring.dev/web"
+)
+
+const maskedValue = "******"
+
+// endpoint is a management endpoint, a prefix endpoint also serves the paths below it.
+type endpoint struct {
+	name    string
+	prefix  bool
+	handler http.HandlerFunc
+}
+
+// management serves the actuator endpoints `beans`, `env`, `configprops`, `loggers`,
+// `health`, `info` and `prometheus` below `management.base-path`, on the main http
+// server or on a separate one when `management.server.addr` is set. Only the read-only
+// endpoints `health` and `info` are exposed by default, the others are listed explicitly
+// by `management.endpoints.include`.
+type management struct {
+	Logger   *slog.Logger       `logger:""`
+	Context  gs.Context         `autowire:""`
+	Router   web.Router         `autowire:"default?"`
+	Health   *gs.HealthRegistry `autowire:"?"`
+	Metrics  *metrics.Registry  `autowire:"?"`
+	BasePath string             `value:"${management.base-path:=/actuator}"`
+	Include  []string           `value:"${management.endpoints.include:=health,info}"`
+	Exclude  []string           `value:"${management.endpoints.exclude:=}"`
+	Masks    []string           `value:"${management.mask:=password,secret,token,credential,key}"`
+	Addr     string             `value:"${management.server.addr:=}"`
+	Mode     string             `value:"${management.server.socket-mode:=0660}"`
+
+	server *http.Server
+}
+
+func (m *management) OnInit(ctx context.Context) error {
+
+	if m.Addr == "" && m.Router == nil {
+		return errors.New("management endpoints require `http.addr` or `management.server.addr`")
+	}
+
+	basePath := "/" + strings.Trim(m.BasePath, "/")
+	endpoints := []endpoint{
+		{name: "beans", handler: m.beans},
+		{name: "env", handler: m.env},
+		{name: "configprops", handler: m.configProps},
+		{name: "loggers", prefix: true, handler: m.loggers},
+		{name: "health", prefix: true, handler: m.health},
+		{name: "info", handler: m.info},
+	}
+	if m.Metrics != nil {
+		endpoints = append(endpoints, endpoint{name: "prometheus", handler: m.Metrics.Handler().ServeHTTP})
+	}
+
+	var mux *http.ServeMux
+	if m.Addr != "" {
+		mux = http.NewServeMux()
+		m.server = &http.Server{Addr: m.Addr, Handler: mux}
+	}
+
+	for _, e := range endpoints {
+		if !m.exposed(e.name) {
+			continue
+		}
+		path := basePath + "/" + e.name
+		if mux != nil {
+			mux.HandleFunc(path, e.handler)
+			if e.prefix {
+				mux.HandleFunc(path+"/", e.handler)
+			}
+			continue
+		}
+		m.Router.HandleFunc(path, e.handler)
+		if e.prefix {
+			m.Router.HandleFunc(path+"/{name}", e.handler)
+		}
+	}
+	return nil
+}
+
+// exposed returns whether the endpoint is included and not excluded.
+func (m *management) exposed(name string) bool {
+	for _, s := range m.Exclude {
+		if s == name || s == "*" {
+			return false
+		}
+	}
+	for _, s := range m.Include {
+		if s == name || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *management) OnAppStart(ctx context.Context) {
+	if m.server == nil {
+		return
+	}
+	mode, err := listener.ParseMode(m.Mode)
+	if err != nil {
+		panic(fmt.Errorf("failed to start management server `%s`: %w", m.Addr, err))
+	}
+	ln, err := listener.Listen(m.Addr, mode)
+	if err != nil {
+		panic(fmt.Errorf("failed to start management server `%s`: %w", m.Addr, err))
+	}
+	m.Logger.Info("starting management server", slog.String("addr", m.Addr))
+	go func() {
+		if err := m.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(fmt.Errorf("failed to start management server `%s`: %w", m.Addr, err))
+		}
+	}()
+}
+
+func (m *management) OnAppStop(ctx context.Context) {
+	if m.server == nil {
+		return
+	}
+	if err := m.server.Shutdown(ctx); err != nil {
+		m.Logger.Error("management server shutdown failed", slog.String("addr", m.Addr), slog.Any("err", err))
+	}
+}
+
+// masked returns whether the value of the key should be masked.
+func (m *management) masked(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range m.Masks {
+		if s != "" && strings.Contains(key, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *management) beans(w http.ResponseWriter, r *http.Request) {
+
+	type beanInfo struct {
+		Name    string   `json:"name"`
+		Type    string   `json:"type"`
+		Aliases []string `json:"aliases,omitempty"`
+		Labels  []string `json:"labels,omitempty"`
+		Exports []string `json:"exports,omitempty"`
+		Primary bool     `json:"primary,omitempty"`
+		Source  string   `json:"source"`
+	}
+
+	beans := make([]beanInfo, 0)
+	for _, b := range m.Context.Beans() {
+		var exports []string
+		for _, t := range b.Exports() {
+			exports = append(exports, t.String())
+		}
+		beans = append(beans, beanInfo{
+			Name:    b.BeanName(),
+			Type:    b.Type().String(),
+			Aliases: b.Aliases(),
+			Labels:  b.Labels(),
+			Exports: exports,
+			Primary: b.IsPrimary(),
+			Source:  b.FileLine(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"beans": beans})
+}
+
+// env reports the properties and where they come from, such as the files and lines.
+func (m *management) env(w http.ResponseWriter, r *http.Request) {
+	props := make(map[string]string)
+	origins := make(map[string]string)
+	for _, key := range m.Context.Keys() {
+		if origin := m.Context.Origin(key); origin != "" {
+			origins[key] = origin
+		}
+		if m.masked(key) {
+			props[key] = maskedValue
+			continue
+		}
+		props[key] = m.Context.Prop(key)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"properties": props, "origins": origins})
+}
+
+// configProps reports the fields of the beans bound by `value` tags, the nested structs,
+// maps and slices are walked and each leaf is masked by its full path.
+func (m *management) configProps(w http.ResponseWriter, r *http.Request) {
+
+	type property struct {
+		Tag   string      `json:"tag"`
+		Value interface{} `json:"value"`
+	}
+
+	beans := make(map[string]map[string]property)
+	for _, b := range m.Context.Beans() {
+		v := reflect.Indirect(b.Value())
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		props := make(map[string]property)
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			tag, ok := f.Tag.Lookup("value")
+			if !ok {
+				continue
+			}
+			var value interface{} = maskedValue
+			if !m.masked(f.Name) {
+				value = m.configValue(configKey(f, tag), v.Field(i))
+			}
+			props[f.Name] = property{Tag: tag, Value: value}
+		}
+		if len(props) > 0 {
+			beans[b.ID()] = props
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"beans": beans})
+}
+
+// configKey returns the property key of the field, or its name when it has no key.
+func configKey(f reflect.StructField, tag string) string {
+	if parsed, err := conf.ParseTag(tag); err == nil && parsed.Key != "" {
+		return parsed.Key
+	}
+	return f.Name
+}
+
+// configValue renders the value of the path, the values of the paths containing a mask
+// are masked, and the leaves are rendered by fmt.
+func (m *management) configValue(path string, v reflect.Value) interface{} {
+	if m.masked(path) {
+		return maskedValue
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return m.configValue(path, v.Elem())
+	case reflect.Struct:
+		if v.CanInterface() {
+			if _, ok := v.Interface().(fmt.Stringer); ok {
+				break
+			}
+		}
+		ret := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			key := f.Name
+			if tag, ok := f.Tag.Lookup("value"); ok {
+				key = configKey(f, tag)
+			}
+			if m.masked(f.Name) {
+				ret[f.Name] = maskedValue
+				continue
+			}
+			ret[f.Name] = m.configValue(path+"."+key, v.Field(i))
+		}
+		return ret
+	case reflect.Map:
+		ret := make(map[string]interface{})
+		iter := v.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key())
+			ret[k] = m.configValue(path+"."+k, iter.Value())
+		}
+		return ret
+	case reflect.Slice, reflect.Array:
+		ret := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret = append(ret, m.configValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i)))
+		}
+		return ret
+	}
+	return fmt.Sprint(v)
+}
+
+// loggers lists the loggers, reports a logger at `loggers/<name>`, and changes the level
+// of a logger when a json such as `{"configuredLevel":"DEBUG"}` is posted to it, a null
+// level restores the level of its handler.
+func (m *management) loggers(w http.ResponseWriter, r *http.Request) {
+
+	type loggerInfo struct {
+		ConfiguredLevel *string `json:"configuredLevel"`
+		EffectiveLevel  string  `json:"effectiveLevel"`
+		Primary         bool    `json:"primary,omitempty"`
+	}
+
+	loggers := make(map[string]loggerInfo)
+	for _, l := range gs.LoggerLevels() {
+		info := loggerInfo{EffectiveLevel: l.Effective.String(), Primary: l.Primary}
+		if l.Configured != nil {
+			s := l.Configured.String()
+			info.ConfiguredLevel = &s
+		}
+		loggers[l.Name] = info
+	}
+
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/loggers")+len("/loggers"):]
+	name = strings.Trim(name, "/")
+	if name == "" {
+		levels := []string{"DEBUG", "INFO", "WARN", "ERROR"}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"levels": levels, "loggers": loggers})
+		return
+	}
+
+	info, ok := loggers[name]
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("logger %q not found", name)})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, info)
+	case http.MethodPost:
+		var req struct {
+			ConfiguredLevel *string `json:"configuredLevel"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		var level *slog.Level
+		if req.ConfiguredLevel != nil {
+			level = new(slog.Level)
+			if err := level.UnmarshalText([]byte(*req.ConfiguredLevel)); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		gs.SetLoggerLevel(name, level)
+		m.Logger.Info("logger level changed", slog.String("name", name), slog.Any("level", req.ConfiguredLevel))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// health reports the aggregated health, or the health of a group at `health/<group>`.
+func (m *management) health(w http.ResponseWriter, r *http.Request) {
+	if m.Health == nil {
+		writeHealth(w, gs.HealthReport{State: gs.HealthUp})
+		return
+	}
+	group := r.URL.Path[strings.LastIndex(r.URL.Path, "/health")+len("/health"):]
+	if group = strings.Trim(group, "/"); group == "" {
+		writeHealth(w, m.Health.Health(r.Context()))
+		return
+	}
+	report, ok := m.Health.Group(r.Context(), group)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("health group %q not found", group)})
+		return
+	}
+	writeHealth(w, report)
+}
+
+// info reports the properties below `info.` and the build information.
+func (m *management) info(w http.ResponseWriter, r *http.Request) {
+	info := map[string]interface{}{
+		"build": map[string]string{
+			"go":        runtime.Version(),
+			"go-spring": gs.Version,
+		},
+	}
+	var keys []string
+	for _, key := range m.Context.Keys() {
+		if strings.HasPrefix(key, "info.") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		info[strings.TrimPrefix(key, "info.")] = m.Context.Prop(key)
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
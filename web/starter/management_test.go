@@ -0,0 +1,229 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+type gsContext = gs.Context
+
+// testContext provides the properties and the beans of the management endpoints.
+type testContext struct {
+	gsContext
+	props *conf.Properties
+	beans []*gs.BeanDefinition
+}
+
+func (c *testContext) Keys() []string {
+	return c.props.Keys()
+}
+
+func (c *testContext) Prop(key string, opts ...conf.GetOption) string {
+	return c.props.Get(key, opts...)
+}
+
+func (c *testContext) Origin(key string) string {
+	return c.props.Origin(key)
+}
+
+func (c *testContext) Beans() []*gs.BeanDefinition {
+	return c.beans
+}
+
+func newTestManagement(ctx *testContext) *management {
+	return &management{
+		Logger:  slog.Default(),
+		Context: ctx,
+		Include: []string{"*"},
+		Masks:   []string{"password", "secret", "token", "credential", "key"},
+	}
+}
+
+// serve calls the handler and decodes the json response into v.
+func serve(t *testing.T, handler http.HandlerFunc, method, path, body string, v interface{}) int {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if v != nil && w.Body.Len() > 0 {
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), v))
+	}
+	return w.Code
+}
+
+func TestManagement_Env(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("app.name", "demo", conf.From("config/application.yaml:2")))
+	assert.Nil(t, p.Set("db.password", "p@ss", conf.From("env GS_DB_PASSWORD")))
+	assert.Nil(t, p.Set("db.user", "root"))
+	m := newTestManagement(&testContext{props: p})
+
+	var resp struct {
+		Properties map[string]string `json:"properties"`
+		Origins    map[string]string `json:"origins"`
+	}
+	code := serve(t, m.env, http.MethodGet, "/actuator/env", "", &resp)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, resp.Properties, map[string]string{
+		"app.name":    "demo",
+		"db.password": maskedValue,
+		"db.user":     "root",
+	})
+	assert.Equal(t, resp.Origins, map[string]string{
+		"app.name":    "config/application.yaml:2",
+		"db.password": "env GS_DB_PASSWORD",
+	})
+}
+
+type testDBConfig struct {
+	User     string `value:"${user}"`
+	Password string `value:"${password}"`
+}
+
+type testConfigBean struct {
+	Name    string            `value:"${app.name}"`
+	Token   string            `value:"${app.auth}"`
+	DB      testDBConfig      `value:"${db}"`
+	Replica []testDBConfig    `value:"${replicas}"`
+	Options map[string]string `value:"${options}"`
+	Secret  *testDBConfig     `value:"${other}"`
+	ignored string
+}
+
+func TestManagement_ConfigProps(t *testing.T) {
+	bean := &testConfigBean{
+		Name:    "demo",
+		Token:   "t0ken",
+		DB:      testDBConfig{User: "root", Password: "p@ss"},
+		Replica: []testDBConfig{{User: "ro", Password: "p@ss2"}},
+		Options: map[string]string{"api-key": "k", "timeout": "3s"},
+		Secret:  &testDBConfig{User: "u"},
+	}
+	b := gs.NewBean(reflect.ValueOf(bean))
+	m := newTestManagement(&testContext{props: conf.New(), beans: []*gs.BeanDefinition{b}})
+
+	var resp struct {
+		Beans map[string]map[string]struct {
+			Tag   string      `json:"tag"`
+			Value interface{} `json:"value"`
+		} `json:"beans"`
+	}
+	code := serve(t, m.configProps, http.MethodGet, "/actuator/configprops", "", &resp)
+	assert.Equal(t, code, http.StatusOK)
+
+	props := resp.Beans[b.ID()]
+	assert.Equal(t, len(props), 6)
+	assert.Equal(t, props["Name"].Value, "demo")
+	assert.Equal(t, props["Token"].Value, maskedValue)
+	assert.Equal(t, props["DB"].Tag, "${db}")
+	assert.Equal(t, props["DB"].Value, map[string]interface{}{"User": "root", "Password": maskedValue})
+	assert.Equal(t, props["Replica"].Value, []interface{}{
+		map[string]interface{}{"User": "ro", "Password": maskedValue},
+	})
+	assert.Equal(t, props["Options"].Value, map[string]interface{}{"api-key": maskedValue, "timeout": "3s"})
+	assert.Equal(t, props["Secret"].Value, maskedValue)
+}
+
+func TestManagement_Exposed(t *testing.T) {
+	cases := []struct {
+		include []string
+		exclude []string
+		name    string
+		expect  bool
+	}{
+		{[]string{"*"}, nil, "env", true},
+		{[]string{"health", "info"}, nil, "env", false},
+		{[]string{"health", "info"}, nil, "info", true},
+		{[]string{"*"}, []string{"env"}, "env", false},
+		{[]string{"*"}, []string{"env"}, "health", true},
+		{[]string{"env"}, []string{"*"}, "env", false},
+		{nil, nil, "env", false},
+	}
+	for _, c := range cases {
+		m := &management{Include: c.include, Exclude: c.exclude}
+		assert.Equal(t, m.exposed(c.name), c.expect)
+	}
+
+	m := new(management)
+	assert.Nil(t, conf.New().Bind(m))
+	assert.Equal(t, m.Include, []string{"health", "info"})
+	for _, name := range []string{"beans", "env", "configprops", "loggers", "prometheus"} {
+		assert.False(t, m.exposed(name))
+	}
+}
+
+func TestManagement_Loggers(t *testing.T) {
+	const name = "management-test"
+	gs.SetLogger(name, slog.New(slog.NewTextHandler(new(strings.Builder), &slog.HandlerOptions{Level: slog.LevelWarn})))
+	m := newTestManagement(&testContext{props: conf.New()})
+	path := "/actuator/loggers/" + name
+
+	type loggerInfo struct {
+		ConfiguredLevel *string `json:"configuredLevel"`
+		EffectiveLevel  string  `json:"effectiveLevel"`
+	}
+
+	var list struct {
+		Levels  []string              `json:"levels"`
+		Loggers map[string]loggerInfo `json:"loggers"`
+	}
+	code := serve(t, m.loggers, http.MethodGet, "/actuator/loggers", "", &list)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, list.Levels, []string{"DEBUG", "INFO", "WARN", "ERROR"})
+	assert.Equal(t, list.Loggers[name], loggerInfo{EffectiveLevel: "WARN"})
+
+	var info loggerInfo
+	code = serve(t, m.loggers, http.MethodGet, path, "", &info)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, info, loggerInfo{EffectiveLevel: "WARN"})
+
+	code = serve(t, m.loggers, http.MethodPost, path, `{"configuredLevel":"DEBUG"}`, nil)
+	assert.Equal(t, code, http.StatusNoContent)
+	code = serve(t, m.loggers, http.MethodGet, path, "", &info)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, *info.ConfiguredLevel, "DEBUG")
+	assert.Equal(t, info.EffectiveLevel, "DEBUG")
+
+	code = serve(t, m.loggers, http.MethodPost, path, `{"configuredLevel":null}`, nil)
+	assert.Equal(t, code, http.StatusNoContent)
+	info = loggerInfo{}
+	code = serve(t, m.loggers, http.MethodGet, path, "", &info)
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, info, loggerInfo{EffectiveLevel: "WARN"})
+
+	var errResp map[string]string
+	code = serve(t, m.loggers, http.MethodPost, path, `{"configuredLevel":"LOUD"}`, &errResp)
+	assert.Equal(t, code, http.StatusBadRequest)
+	code = serve(t, m.loggers, http.MethodPost, path, `{`, &errResp)
+	assert.Equal(t, code, http.StatusBadRequest)
+
+	code = serve(t, m.loggers, http.MethodGet, "/actuator/loggers/unknown", "", &errResp)
+	assert.Equal(t, code, http.StatusNotFound)
+	assert.Equal(t, errResp["error"], `logger "unknown" not found`)
+
+	code = serve(t, m.loggers, http.MethodDelete, path, "", nil)
+	assert.Equal(t, code, http.StatusMethodNotAllowed)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-spring.dev/spring/metrics"
+)
+
+// httpMetrics instruments the requests served by the http servers, the serverManager
+// mounts its middleware on the router of each server.
+type httpMetrics struct {
+	Registry *metrics.Registry `autowire:""`
+
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	inflight *metrics.GaugeVec
+}
+
+func (hm *httpMetrics) OnInit(ctx context.Context) error {
+	hm.requests = hm.Registry.Counter("http_server_requests_total",
+		"The number of http requests by server, method and status code.", "server", "method", "code")
+	hm.duration = hm.Registry.Histogram("http_server_request_duration_seconds",
+		"The latency of http requests by server, method and status code.", nil, "server", "method", "code")
+	hm.inflight = hm.Registry.Gauge("http_server_requests_in_flight",
+		"The number of http requests being served by server.", "server")
+	return nil
+}
+
+// middleware returns the middleware instrumenting the requests of the server.
+func (hm *httpMetrics) middleware(server string) func(http.Handler) http.Handler {
+	inflight := hm.inflight.With(server)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			inflight.Inc()
+			sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+			defer func() {
+				inflight.Dec()
+				method, code := methodLabel(r.Method), strconv.Itoa(sw.code)
+				hm.requests.With(server, method, code).Inc()
+				hm.duration.With(server, method, code).Observe(time.Since(start).Seconds())
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// methodLabel returns the method label of the request, the unknown methods share the
+// label "OTHER" so that clients can't grow the metrics without bound.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// statusWriter records the status code written to the response.
+type statusWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer supports it.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
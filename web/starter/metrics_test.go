@@ -0,0 +1,66 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/spring/metrics"
+	"go-spring.dev/web"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	router := web.NewRouter()
+	hm := &httpMetrics{Registry: metrics.NewRegistry()}
+	assert.Nil(t, hm.OnInit(context.Background()))
+	router.Use(hm.middleware("default"))
+
+	router.HandleFunc("/created", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, hm.inflight.With("default").Value(), float64(1))
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	router.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		assert.True(t, ok)
+		f.Flush()
+		_, _, err := w.(http.Hijacker).Hijack()
+		assert.True(t, errors.Is(err, http.ErrNotSupported))
+	})
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPost, "BREW"} {
+		assert.Equal(t, serve(t, router.ServeHTTP, method, "/created", "", nil), http.StatusCreated)
+	}
+	serve(t, router.ServeHTTP, http.MethodGet, "/stream", "", nil)
+	serve(t, router.ServeHTTP, http.MethodGet, "/missing", "", nil)
+
+	assert.Equal(t, hm.requests.With("default", "GET", "201").Value(), float64(1))
+	assert.Equal(t, hm.requests.With("default", "POST", "201").Value(), float64(2))
+	assert.Equal(t, hm.requests.With("default", "OTHER", "201").Value(), float64(1))
+	assert.Equal(t, hm.requests.With("default", "GET", "200").Value(), float64(1))
+	assert.Equal(t, hm.requests.With("default", "GET", "404").Value(), float64(1))
+	assert.Equal(t, hm.inflight.With("default").Value(), float64(0))
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, hm.Registry.WriteText(w))
+	assert.String(t, w.Body.String()).Contains(`http_server_requests_total{server="default",method="OTHER",code="201"} 1`)
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/web/starter/internal/listener"
+	"go-spring.dev/spring/web/starter/internal/tlsconfig"
+)
+
+// serverOptions is the configuration of a http server, such as the properties `http`.
+// The address is a tcp address, `unix:<path>` or `systemd:<name>`.
+type serverOptions struct {
+	Addr              string            `value:"${addr}"`
+	SocketMode        string            `value:"${socket-mode:=0660}"`
+	ReadTimeout       time.Duration     `value:"${read-timeout:=0s}"`
+	ReadHeaderTimeout time.Duration     `value:"${read-header-timeout:=0s}"`
+	WriteTimeout      time.Duration     `value:"${write-timeout:=0s}"`
+	IdleTimeout       time.Duration     `value:"${idle-timeout:=0s}"`
+	MaxHeaderBytes    int               `value:"${max-header-bytes:=0}"`
+	TLS               tlsconfig.Options `value:"${tls}"`
+}
+
+// httpServer serves a handler with the options, over tls when it's configured.
+type httpServer struct {
+	name    string
+	options serverOptions
+	logger  *slog.Logger
+	server  *http.Server
+	tls     *tlsconfig.Config
+	mode    fs.FileMode
+}
+
+func newHTTPServer(name string, options serverOptions, handler http.Handler, logger *slog.Logger) (*httpServer, error) {
+	s := &httpServer{
+		name:    name,
+		options: options,
+		logger:  logger.With(slog.String("server", name), slog.String("addr", options.Addr)),
+		server: &http.Server{
+			Addr:              options.Addr,
+			Handler:           handler,
+			ReadTimeout:       options.ReadTimeout,
+			ReadHeaderTimeout: options.ReadHeaderTimeout,
+			WriteTimeout:      options.WriteTimeout,
+			IdleTimeout:       options.IdleTimeout,
+			MaxHeaderBytes:    options.MaxHeaderBytes,
+		},
+	}
+	mode, err := listener.ParseMode(options.SocketMode)
+	if err != nil {
+		return nil, fmt.Errorf("http server `%s` error: %w", name, err)
+	}
+	s.mode = mode
+	if options.TLS.Enabled() {
+		c, err := tlsconfig.New(options.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("http server `%s` tls error: %w", name, err)
+		}
+		s.tls = c
+		s.server.TLSConfig = c.TLSConfig()
+	}
+	return s, nil
+}
+
+// start listens on the address and serves in background, the certificates are reloaded
+// by a goroutine of the IoC container when they change. A socket passed by systemd socket
+// activation with the address is used when available.
+func (s *httpServer) start(ctx gs.Context) error {
+
+	ln, err := listener.Listen(s.options.Addr, s.mode)
+	if err != nil {
+		return fmt.Errorf("failed to start http server `%s`: %w", s.options.Addr, err)
+	}
+
+	if s.tls != nil {
+		ln = tls.NewListener(ln, s.server.TLSConfig)
+		ctx.Go(func(ctx context.Context) {
+			s.tls.Watch(ctx, func(err error) {
+				if err != nil {
+					s.logger.Error("reload tls certificates failed", slog.Any("err", err))
+				} else {
+					s.logger.Info("tls certificates reloaded")
+				}
+			})
+		}, gs.GoName("http-tls-reload-"+s.name))
+	}
+
+	s.logger.Info("starting http server", slog.Bool("tls", s.tls != nil))
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(fmt.Errorf("failed to serve http server `%s`: %w", s.options.Addr, err))
+		}
+	}()
+	return nil
+}
+
+func (s *httpServer) stop(ctx context.Context) {
+	s.logger.Info("stopping http server")
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.logger.Error("http server shutdown failed", slog.Any("err", err))
+	} else {
+		s.logger.Info("http server shutdown successfully")
+	}
+}
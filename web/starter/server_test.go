@@ -0,0 +1,69 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-spring.dev/spring/internal/utils/assert"
+)
+
+func TestHTTPServer(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	t.Run("socket mode", func(t *testing.T) {
+		_, err := newHTTPServer("test", serverOptions{Addr: ":0", SocketMode: "rw"}, handler, slog.Default())
+		assert.Error(t, err, "http server `test` error: ")
+	})
+
+	t.Run("listen", func(t *testing.T) {
+		options := serverOptions{Addr: "unix:" + filepath.Join(t.TempDir(), "none", "http.sock"), SocketMode: "0660"}
+		s, err := newHTTPServer("test", options, handler, slog.Default())
+		assert.Nil(t, err)
+		assert.Error(t, s.start(nil), "failed to start http server `unix:.*`")
+	})
+
+	t.Run("serve", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "http.sock")
+		options := serverOptions{Addr: "unix:" + path, SocketMode: "0600", ReadTimeout: time.Second}
+		s, err := newHTTPServer("test", options, handler, slog.Default())
+		assert.Nil(t, err)
+		assert.Equal(t, s.server.ReadTimeout, time.Second)
+		assert.Nil(t, s.start(nil))
+
+		fi, err := os.Stat(path)
+		assert.Nil(t, err)
+		assert.Equal(t, fi.Mode().Perm(), os.FileMode(0600))
+
+		code, body, _ := get(t, unixClient(path), "/")
+		assert.Equal(t, code, http.StatusOK)
+		assert.Equal(t, body, "ok")
+
+		s.stop(context.Background())
+		_, err = unixClient(path).Get("http://localhost/")
+		assert.NotNil(t, err)
+	})
+}
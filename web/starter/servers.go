@@ -0,0 +1,258 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/web/starter/internal/pathmatch"
+	"go-spring.dev/web"
+)
+
+// defaultServer is the name of the server configured by `http.addr`.
+const defaultServer = "default"
+
+// RouteRegistrar is implemented by beans registering routes, the beans must export the
+// interface and are applied in the ascending order of their bean Order.
+//
+//	gs.Object(new(UserController)).Export((*starter.RouteRegistrar)(nil))
+type RouteRegistrar interface {
+	RegisterRoutes(router web.Router) error
+}
+
+// Middleware is implemented by beans wrapping the handlers of the servers, the beans must
+// export the interface and are mounted in the ascending order of their bean Order, then
+// of their bean names. The paths a middleware applies to are filtered by the properties
+// `http.middlewares.<bean name>`.
+//
+//	http.middlewares.auth.include=/api/**
+//	http.middlewares.auth.exclude=/api/login
+type Middleware interface {
+	Middleware(next http.Handler) http.Handler
+}
+
+// ServerTarget is optionally implemented by a RouteRegistrar or a Middleware to choose the
+// servers it applies to, a RouteRegistrar registers routes on the default server otherwise,
+// and a Middleware is mounted on all servers.
+type ServerTarget interface {
+	TargetServers() []string
+}
+
+// middlewareOptions filters the request paths of a middleware.
+type middlewareOptions struct {
+	Include []string `value:"${include:=}"`
+	Exclude []string `value:"${exclude:=}"`
+}
+
+// pathMiddleware applies a middleware to the request paths matched by the matcher.
+type pathMiddleware struct {
+	name    string
+	m       Middleware
+	matcher *pathmatch.Matcher
+}
+
+func (pm *pathMiddleware) wrap(next http.Handler) http.Handler {
+	h := pm.m.Middleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pm.matcher.Match(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// registerServers registers a router bean for each `http.servers.<name>`, the bean is
+// named by the name of the server, and the bean managing the servers.
+func registerServers(r gs.BeanDefinitionRegistry) error {
+
+	var servers map[string]serverOptions
+	if err := r.Properties().Bind(&servers, conf.Key("http.servers")); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		if name == defaultServer {
+			return fmt.Errorf("http server name %q is reserved for `http.addr`", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.Provide(web.NewRouter).Name(name)
+	}
+
+	if r.Properties().Has("http.addr") {
+		var options serverOptions
+		if err := r.Properties().Bind(&options, conf.Key("http")); err != nil {
+			return err
+		}
+		if servers == nil {
+			servers = make(map[string]serverOptions)
+		}
+		servers[defaultServer] = options
+		names = append([]string{defaultServer}, names...)
+	}
+	if len(names) > 0 {
+		r.Object(&serverManager{names: names, servers: servers})
+	}
+	return nil
+}
+
+// serverManager builds the routes of the servers, and starts and stops them with the application.
+type serverManager struct {
+	Logger      *slog.Logger          `logger:""`
+	Context     gs.Context            `autowire:""`
+	Registrars  []RouteRegistrar      `autowire:"?"`
+	Middlewares map[string]Middleware `autowire:"?"`
+	Metrics     *httpMetrics          `autowire:"?"`
+
+	names   []string
+	servers map[string]serverOptions
+	routers map[string]web.Router
+	running []*httpServer
+}
+
+func (sm *serverManager) OnInit(ctx context.Context) error {
+
+	sm.routers = make(map[string]web.Router)
+	for _, name := range sm.names {
+		var router web.Router
+		if err := sm.Context.Get(&router, name); err != nil {
+			return fmt.Errorf("router of http server %q error: %w", name, err)
+		}
+		sm.routers[name] = router
+		// the requests are measured with the middlewares.
+		if sm.Metrics != nil {
+			router.Use(sm.Metrics.middleware(name))
+		}
+	}
+
+	// the middlewares are mounted before the routes are registered.
+	middlewares, err := sm.sortedMiddlewares()
+	if err != nil {
+		return err
+	}
+	for _, pm := range middlewares {
+		var targets []string
+		if t, ok := pm.m.(ServerTarget); ok {
+			targets = t.TargetServers()
+		} else {
+			targets = sm.names
+		}
+		for _, target := range targets {
+			router, ok := sm.routers[target]
+			if !ok {
+				return fmt.Errorf("middleware %q targets unknown http server %q", pm.name, target)
+			}
+			router.Use(pm.wrap)
+		}
+	}
+
+	for _, registrar := range sm.Registrars {
+		targets := []string{defaultServer}
+		if t, ok := registrar.(ServerTarget); ok {
+			targets = t.TargetServers()
+		}
+		for _, target := range targets {
+			router, ok := sm.routers[target]
+			if !ok {
+				return fmt.Errorf("route registrar %T targets unknown http server %q", registrar, target)
+			}
+			if err := registrar.RegisterRoutes(router); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortedMiddlewares returns the middlewares sorted by their bean Order, then by their
+// bean names, with the path filters of the properties `http.middlewares.<bean name>`.
+func (sm *serverManager) sortedMiddlewares() ([]*pathMiddleware, error) {
+	if len(sm.Middlewares) == 0 {
+		return nil, nil
+	}
+
+	order := make(map[string]float32)
+	t := reflect.TypeOf((*Middleware)(nil)).Elem()
+	for _, b := range sm.Context.Beans() {
+		if _, ok := sm.Middlewares[b.BeanName()]; !ok {
+			continue
+		}
+		for _, e := range append(b.Exports(), b.Type()) {
+			if e == t {
+				order[b.BeanName()] = b.GetOrder()
+			}
+		}
+	}
+
+	names := make([]string, 0, len(sm.Middlewares))
+	for name := range sm.Middlewares {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if order[names[i]] != order[names[j]] {
+			return order[names[i]] < order[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var ret []*pathMiddleware
+	for _, name := range names {
+		var options middlewareOptions
+		if err := sm.Context.Bind(&options, conf.Key("http.middlewares."+name)); err != nil {
+			return nil, err
+		}
+		matcher, err := pathmatch.New(options.Include, options.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %q error: %w", name, err)
+		}
+		ret = append(ret, &pathMiddleware{name: name, m: sm.Middlewares[name], matcher: matcher})
+	}
+	return ret, nil
+}
+
+func (sm *serverManager) OnAppStart(ctx context.Context) {
+	gsCtx := gs.FromContext(ctx)
+	for _, name := range sm.names {
+		server, err := newHTTPServer(name, sm.servers[name], sm.routers[name], sm.Logger)
+		if err == nil {
+			err = server.start(gsCtx)
+		}
+		if err != nil {
+			panic(err)
+		}
+		sm.running = append(sm.running, server)
+	}
+}
+
+func (sm *serverManager) OnAppStop(ctx context.Context) {
+	for i := len(sm.running) - 1; i >= 0; i-- {
+		sm.running[i].stop(ctx)
+	}
+	sm.running = nil
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package starter
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-spring.dev/spring/conf"
+	"go-spring.dev/spring/gs"
+	"go-spring.dev/spring/internal/utils/assert"
+	"go-spring.dev/spring/metrics"
+	"go-spring.dev/web"
+)
+
+// chainMiddleware appends its name to the header `X-Chain` of the response.
+type chainMiddleware struct {
+	name    string
+	targets []string
+}
+
+func (m *chainMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Chain", m.name)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// targetMiddleware is a chainMiddleware mounted on the target servers only.
+type targetMiddleware struct {
+	chainMiddleware
+}
+
+func (m *targetMiddleware) TargetServers() []string {
+	return m.targets
+}
+
+// routes registers the paths on the default server, or on the target servers.
+type routes struct {
+	paths []string
+}
+
+func (rs *routes) RegisterRoutes(router web.Router) error {
+	for _, path := range rs.paths {
+		router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.URL.Path)
+		})
+	}
+	return nil
+}
+
+type targetRoutes struct {
+	routes
+	targets []string
+}
+
+func (rs *targetRoutes) TargetServers() []string {
+	return rs.targets
+}
+
+// unixClient returns a client sending all requests to the unix socket.
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+// get returns the status code, the body and the header `X-Chain` of the response.
+func get(t *testing.T, client *http.Client, path string) (int, string, string) {
+	resp, err := client.Get("http://localhost" + path)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	return resp.StatusCode, string(b), strings.Join(resp.Header.Values("X-Chain"), ",")
+}
+
+func TestServers(t *testing.T) {
+	dir := t.TempDir()
+	defaultSock := filepath.Join(dir, "default.sock")
+	adminSock := filepath.Join(dir, "admin.sock")
+
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("http.addr", "unix:"+defaultSock))
+	assert.Nil(t, p.Set("http.servers.admin.addr", "unix:"+adminSock))
+	assert.Nil(t, p.Set("http.middlewares.auth.include", "/api/**"))
+	assert.Nil(t, p.Set("http.middlewares.auth.exclude", "/api/login"))
+
+	app := gs.NewApp(gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+	app.Object(&chainMiddleware{name: "auth"}).Name("auth").Order(2).Export((*Middleware)(nil))
+	app.Object(&chainMiddleware{name: "trace"}).Name("trace").Order(1).Export((*Middleware)(nil))
+	app.Object(&chainMiddleware{name: "b"}).Name("b").Order(3).Export((*Middleware)(nil))
+	app.Object(&chainMiddleware{name: "a"}).Name("a").Order(3).Export((*Middleware)(nil))
+	app.Object(&targetMiddleware{chainMiddleware{name: "admin", targets: []string{"admin"}}}).
+		Name("admin").Export((*Middleware)(nil))
+	app.Object(&routes{paths: []string{"/api/users", "/api/login"}}).
+		Export((*RouteRegistrar)(nil))
+	app.Object(&targetRoutes{routes{paths: []string{"/info"}}, []string{"default", "admin"}}).
+		Name("infoRoutes").Export((*RouteRegistrar)(nil))
+	app.Object(&targetRoutes{routes{paths: []string{"/admin/users"}}, []string{"admin"}}).
+		Name("adminRoutes").Export((*RouteRegistrar)(nil))
+
+	assert.Nil(t, app.Start(context.Background()))
+	defer func() { assert.Nil(t, app.Stop(context.Background())) }()
+
+	client := unixClient(defaultSock)
+	code, body, chain := get(t, client, "/api/users")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, body, "/api/users")
+	assert.Equal(t, chain, "trace,auth,a,b")
+
+	code, _, chain = get(t, client, "/api/login")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, chain, "trace,a,b")
+
+	code, _, _ = get(t, client, "/info")
+	assert.Equal(t, code, http.StatusOK)
+	code, _, _ = get(t, client, "/admin/users")
+	assert.Equal(t, code, http.StatusNotFound)
+
+	code, _, _ = get(t, client, "/health")
+	assert.Equal(t, code, http.StatusOK)
+	code, _, _ = get(t, client, "/health/readiness")
+	assert.Equal(t, code, http.StatusOK)
+
+	admin := unixClient(adminSock)
+	code, body, chain = get(t, admin, "/admin/users")
+	assert.Equal(t, code, http.StatusOK)
+	assert.Equal(t, body, "/admin/users")
+	assert.Equal(t, chain, "admin,trace,a,b")
+
+	code, _, _ = get(t, admin, "/info")
+	assert.Equal(t, code, http.StatusOK)
+	code, _, chain = get(t, admin, "/api/users")
+	assert.Equal(t, code, http.StatusNotFound)
+	assert.Equal(t, chain, "admin,trace,auth,a,b")
+
+	requests := metrics.Default.Counter("http_server_requests_total", "", "server", "method", "code")
+	assert.Equal(t, requests.With("default", "GET", "200").Value(), float64(5))
+	assert.Equal(t, requests.With("default", "GET", "404").Value(), float64(1))
+	assert.Equal(t, requests.With("admin", "GET", "200").Value(), float64(2))
+	assert.Equal(t, requests.With("admin", "GET", "404").Value(), float64(1))
+}
+
+func TestServers_UnknownTarget(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("http.addr", "unix:"+filepath.Join(t.TempDir(), "default.sock")))
+
+	app := gs.NewApp(gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+	app.Object(&targetRoutes{routes{paths: []string{"/info"}}, []string{"admin"}}).
+		Export((*RouteRegistrar)(nil))
+	err := app.Start(context.Background())
+	assert.Error(t, err, `route registrar \*starter.targetRoutes targets unknown http server "admin"`)
+}
+
+func TestServers_ReservedName(t *testing.T) {
+	p := conf.New()
+	assert.Nil(t, p.Set("spring.config.banner", false))
+	assert.Nil(t, p.Set("http.servers.default.addr", ":0"))
+
+	app := gs.NewApp(gs.WithProperties(p), gs.WithoutSignals(), gs.WithArgs(nil))
+	err := app.Start(context.Background())
+	assert.Error(t, err, "http server name \"default\" is reserved for `http.addr`")
+}